@@ -2,7 +2,12 @@ module github.com/deny-7/prettytable
 
 go 1.23.4
 
-require github.com/mattn/go-sqlite3 v1.14.28
+require (
+	github.com/mattn/go-sqlite3 v1.14.28
+	golang.org/x/term v0.30.0
+	golang.org/x/text v0.23.0
+	modernc.org/sqlite v1.37.0
+)
 
 require (
 	github.com/dustin/go-humanize v1.0.1 // indirect
@@ -15,5 +20,4 @@ require (
 	modernc.org/libc v1.62.1 // indirect
 	modernc.org/mathutil v1.7.1 // indirect
 	modernc.org/memory v1.9.1 // indirect
-	modernc.org/sqlite v1.37.0 // indirect
 )
@@ -1,13 +1,37 @@
 package prettytable
 
 import (
+	"bytes"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
 	"encoding/csv"
+	"encoding/gob"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
+	"maps"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"text/template"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
 )
 
 // Alignment type for column alignment
@@ -27,13 +51,89 @@ type Table struct {
 	rows       [][]any
 	// alignments stores per-column alignment
 	alignments map[string]Alignment
+	// headerAlignments overrides alignments for header cells
+	headerAlignments map[string]Alignment
+	// headerLines replaces the header text of columns with several lines
+	headerLines map[string][]string
 	// sortBy and reverseSort for sorting
 	sortBy      string
 	reverseSort bool
+	// sortKeys, when set, replaces sortBy with a multi-column sort
+	sortKeys []SortKey
+	// customLess holds per-column comparison functions used when sorting
+	customLess map[string]func(a, b any) bool
+	// sortLocale, if set, names the language whose collation orders strings
+	sortLocale string
 	// rowFilter for filtering
 	rowFilter func([]any) bool
 	// style holds table style options
 	style TableStyle
+	// rowNumberLabel is the header of the RenderWithRowNumbers column
+	rowNumberLabel string
+	// autoNumberField names the column kept numbered by SetAutoNumberRows
+	autoNumberField string
+	autoNumberStart int
+	// dataSeparator draws a rule after every N data rows when positive
+	dataSeparator int
+	// groupSep reports whether a group separator goes between two rows
+	groupSep      func(prev, curr []any) bool
+	groupSepStyle GroupSepStyle
+	// fixedWidths overrides the computed width of columns
+	fixedWidths map[string]int
+	// maxWidths limits the width of columns
+	maxWidths map[string]int
+	// wrapWidths wraps the cells of columns onto several lines
+	wrapWidths map[string]int
+	// autoWrapWidth limits the total width of bordered tables by wrapping
+	// columns without a WrapText setting
+	autoWrapWidth int
+	// rowStyle picks the ANSI and HTML style of each rendered data row
+	rowStyle func(rowIdx int, row []any) RowStyle
+	// highlights holds manual ANSI styles by display row index
+	highlights map[int]CellStyle
+	// cellHighlights holds conditional ANSI styles for cells per column
+	cellHighlights map[string][]cellHighlight
+	// emptyMessage is shown inside the frame when there is nothing to render
+	emptyMessage string
+	// columnPadding overrides the style's left and right padding per column
+	columnPadding map[string][2]int
+	// nullString replaces nil cells when set
+	nullString *string
+	// boolStrings and columnBoolStrings replace true and false cells when set
+	boolStrings       *[2]string
+	columnBoolStrings map[string][2]string
+	// columnFormats holds fmt.Sprintf format strings per column
+	columnFormats map[string]string
+	// encodings shows []byte and string cells of a column in an encoding
+	encodings map[string]ColumnEncoding
+	// timeLayouts holds time.Format layouts for time.Time cells per column
+	timeLayouts map[string]string
+	// defaults fill trailing cells missing from rows passed to AddRow
+	defaults map[string]any
+	// required lists the columns checked by ValidateRequired
+	required map[string]bool
+	// csvDelimiter separates fields in CSV output; 0 means ','
+	csvDelimiter rune
+	// caption is shown above the table, or below it if captionBelow is set
+	caption      string
+	captionBelow bool
+	// latexLabel is the \label of RenderLatexLongtable
+	latexLabel string
+	// comment is embedded as a source comment in HTML and LaTeX output
+	comment string
+	// watchers receive mutation events
+	watchers []chan<- TableEvent
+	// readonly makes mutating methods fail with ErrReadOnly
+	readonly bool
+	// cache holds the last RenderASCII output when enabled
+	cache *renderCache
+}
+
+// renderCache remembers a rendered string and the state it was rendered from
+type renderCache struct {
+	valid  bool
+	hash   uint64
+	output string
 }
 
 // TableStyle holds options for customizing table appearance
@@ -42,11 +142,11 @@ type TableStyle struct {
 	Border                  bool
 	PreserveInternalBorder  bool
 	Header                  bool
-	HRule                   string // "FRAME", "HEADER", "ALL", "NONE"
-	VRule                   string // "FRAME", "ALL", "NONE"
-	IntFormat               string // e.g. ",d" or "03d"
-	FloatFormat             string // e.g. ".2f"
-	CustomFormat            map[string]func(field string, value any) string
+	HRule                   string                                          // "FRAME", "HEADER", "ALL", "NONE"
+	VRule                   string                                          // "FRAME", "ALL", "NONE"
+	IntFormat               string                                          // e.g. ",d" or "03d"
+	FloatFormat             string                                          // e.g. ".2f"
+	CustomFormat            map[string]func(field string, value any) string `json:"-"`
 	PaddingWidth            int
 	LeftPaddingWidth        int
 	RightPaddingWidth       int
@@ -66,10 +166,68 @@ type TableStyle struct {
 	MaxTableWidth           int
 	MaxWidth                int
 	MinWidth                int
+	TruncationMarker        string // appended to cut-off cell content, e.g. "..."
+	PSQLRowCount            bool   // append a "(N rows)" footer to RenderPSQL output
+	ANSIEnabled             bool   // emit the escape codes of row highlights and highlight conditions in RenderANSI
 	UseHeaderWidth          *bool
 	BreakOnHyphens          *bool
 }
 
+// ErrReadOnly is returned when mutating a table created by Snapshot
+var ErrReadOnly = errors.New("table is read-only")
+
+// ErrRequired is wrapped by the errors ValidateRequired reports
+var ErrRequired = errors.New("value is required")
+
+// DefaultNullString is how nil cells are displayed unless a table sets its own
+// with SetNullString.
+var DefaultNullString = ""
+
+// EventType identifies the kind of change described by a TableEvent
+type EventType int
+
+const (
+	RowAdded EventType = iota
+	RowDeleted
+	RowUpdated
+	CellUpdated
+	ColumnsChanged
+	Cleared
+)
+
+// TableEvent describes a mutation of a table. RowIndex is -1 when the change
+// is not tied to a single row; Field is set for cell and column changes.
+type TableEvent struct {
+	Type     EventType
+	RowIndex int
+	Field    string
+	OldValue any
+	NewValue any
+}
+
+// Watch registers ch to receive an event for every mutation of the table.
+// Events are sent without blocking and dropped if ch is full.
+func (t *Table) Watch(ch chan<- TableEvent) {
+	t.watchers = append(t.watchers, ch)
+}
+
+// Unwatch stops sending events to ch
+func (t *Table) Unwatch(ch chan<- TableEvent) {
+	t.watchers = slices.DeleteFunc(t.watchers, func(w chan<- TableEvent) bool { return w == ch })
+}
+
+// emit sends ev to all watchers without blocking. Every change to the rows
+// is reported here, so it also invalidates the render cache.
+func (t *Table) emit(ev TableEvent) {
+	t.InvalidateCache()
+	for _, ch := range t.watchers {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
 // NewTable creates a new empty table
 func NewTable() *Table {
 	return &Table{}
@@ -81,8 +239,13 @@ func NewTableWithFields(fields []string) *Table {
 }
 
 // SetFieldNames sets the field (column) names
-func (t *Table) SetFieldNames(fields []string) {
+func (t *Table) SetFieldNames(fields []string) error {
+	if t.readonly {
+		return ErrReadOnly
+	}
 	t.fieldNames = fields
+	t.emit(TableEvent{Type: ColumnsChanged, RowIndex: -1})
+	return nil
 }
 
 // FieldNames returns the field names
@@ -90,17 +253,273 @@ func (t *Table) FieldNames() []string {
 	return t.fieldNames
 }
 
+// Rows returns a copy of the stored rows
+func (t *Table) Rows() [][]any {
+	return copyRows(t.rows)
+}
+
+// SetRows replaces all rows at once. Every row must match the number of
+// field names; on error the table is left unchanged.
+func (t *Table) SetRows(rows [][]any) error {
+	if t.readonly {
+		return ErrReadOnly
+	}
+	if len(t.fieldNames) > 0 {
+		for i, row := range rows {
+			if len(row) != len(t.fieldNames) {
+				return fmt.Errorf("row %d has %d columns, expected %d", i, len(row), len(t.fieldNames))
+			}
+		}
+	}
+	t.rows = copyRows(rows)
+	t.renumber()
+	t.emit(TableEvent{Type: RowUpdated, RowIndex: -1})
+	return nil
+}
+
+// ColCount returns the number of columns
+func (t *Table) ColCount() int {
+	return len(t.fieldNames)
+}
+
+// RowCount returns the number of rows that would be rendered (after filtering)
+func (t *Table) RowCount() int {
+	if t.rowFilter == nil {
+		return len(t.rows)
+	}
+	n := 0
+	for _, row := range t.rows {
+		if t.rowFilter(row) {
+			n++
+		}
+	}
+	return n
+}
+
+// StoredRowCount returns the number of stored rows, ignoring the row filter
+func (t *Table) StoredRowCount() int {
+	return len(t.rows)
+}
+
+// IsEmpty reports whether no rows would be rendered
+func (t *Table) IsEmpty() bool {
+	return t.RowCount() == 0
+}
+
 // AddRow adds a row to the table
 func (t *Table) AddRow(row []any) error {
+	if t.readonly {
+		return ErrReadOnly
+	}
+	row = t.withNumberCell(t.withDefaults(row))
 	if len(t.fieldNames) > 0 && len(row) != len(t.fieldNames) {
 		return fmt.Errorf("row has %d columns, expected %d", len(row), len(t.fieldNames))
 	}
 	t.rows = append(t.rows, row)
+	t.renumber()
+	t.emit(TableEvent{Type: RowAdded, RowIndex: len(t.rows) - 1, NewValue: row})
+	return nil
+}
+
+// SetDefaultValue sets the value AddRow uses for field when a row is too
+// short to include it. Only trailing columns can be left out, and every
+// column left out must have a default; with SetAutoNumberRows a short row
+// is taken to omit the number column as well. AddRowMap and
+// AddRowMapPartial use the default for keys missing from the map.
+func (t *Table) SetDefaultValue(field string, val any) {
+	if t.defaults == nil {
+		t.defaults = make(map[string]any)
+	}
+	t.defaults[field] = val
+}
+
+// withDefaults appends default values for the trailing columns a row omits
+func (t *Table) withDefaults(row []any) []any {
+	names := t.fieldNames
+	if idx := t.fieldIndex(t.autoNumberField); t.autoNumberField != "" && idx != -1 && len(row) < len(names) {
+		names = slices.Delete(slices.Clone(names), idx, idx+1)
+	}
+	if len(t.defaults) == 0 || len(row) >= len(names) {
+		return row
+	}
+	filled := slices.Clone(row)
+	for _, name := range names[len(row):] {
+		v, ok := t.defaults[name]
+		if !ok {
+			return row
+		}
+		filled = append(filled, v)
+	}
+	return filled
+}
+
+// SetAutoNumberRows prepends a column named label holding the position of
+// each stored row, counting from startAt. The numbers are kept contiguous
+// as rows are added, inserted and deleted, and new rows may omit the column.
+// Calling it again changes the label and start of the existing column.
+func (t *Table) SetAutoNumberRows(label string, startAt int) error {
+	if t.readonly {
+		return ErrReadOnly
+	}
+	if label != t.autoNumberField && t.fieldIndex(label) != -1 {
+		return fmt.Errorf("column %q already exists", label)
+	}
+	if t.autoNumberField == "" {
+		if err := t.InsertColumn(0, label, make([]any, len(t.rows))); err != nil {
+			return err
+		}
+	} else if err := t.RenameColumn(t.autoNumberField, label); err != nil {
+		return err
+	}
+	t.autoNumberField = label
+	t.autoNumberStart = startAt
+	t.renumber()
+	return nil
+}
+
+// ClearAutoNumberRows removes the column added by SetAutoNumberRows.
+func (t *Table) ClearAutoNumberRows() error {
+	if t.autoNumberField == "" {
+		return nil
+	}
+	return t.DelColumn(t.autoNumberField)
+}
+
+// withNumberCell inserts a placeholder for the auto-number column into a row
+// that omits it. The row is copied so numbering never writes to the
+// caller's slice.
+func (t *Table) withNumberCell(row []any) []any {
+	idx := t.fieldIndex(t.autoNumberField)
+	if t.autoNumberField == "" || idx == -1 {
+		return row
+	}
+	if len(row) != len(t.fieldNames)-1 {
+		return slices.Clone(row)
+	}
+	return slices.Insert(slices.Clone(row), idx, any(nil))
+}
+
+// renumber refreshes the auto-number column, if any
+func (t *Table) renumber() {
+	idx := t.fieldIndex(t.autoNumberField)
+	if t.autoNumberField == "" || idx == -1 {
+		return
+	}
+	for i, row := range t.rows {
+		if idx < len(row) {
+			row[idx] = t.autoNumberStart + i
+		}
+	}
+}
+
+// AddRowMap adds a row given as a map from field name to value.
+// Every field must be present in m; keys that are not field names are ignored.
+func (t *Table) AddRowMap(m map[string]any) error {
+	row := make([]any, len(t.fieldNames))
+	for i, name := range t.fieldNames {
+		v, ok := m[name]
+		if !ok {
+			v, ok = t.defaults[name]
+		}
+		if !ok && name != t.autoNumberField {
+			return fmt.Errorf("row is missing field %q", name)
+		}
+		row[i] = v
+	}
+	return t.AddRow(row)
+}
+
+// AddRowMapPartial is like AddRowMap but uses nil for fields missing from m
+// that have no default value.
+func (t *Table) AddRowMapPartial(m map[string]any) error {
+	row := make([]any, len(t.fieldNames))
+	for i, name := range t.fieldNames {
+		v, ok := m[name]
+		if !ok {
+			v = t.defaults[name]
+		}
+		row[i] = v
+	}
+	return t.AddRow(row)
+}
+
+// GetRowMap returns the stored row at index as a map from field name to value.
+func (t *Table) GetRowMap(index int) (map[string]any, error) {
+	if index < 0 || index >= len(t.rows) {
+		return nil, fmt.Errorf("row index %d out of range", index)
+	}
+	m := make(map[string]any, len(t.fieldNames))
+	for i, name := range t.fieldNames {
+		if i < len(t.rows[index]) {
+			m[name] = t.rows[index][i]
+		}
+	}
+	return m, nil
+}
+
+// CellAt returns the value of field in the stored row at rowIdx, ignoring
+// the row filter and sorting.
+func (t *Table) CellAt(rowIdx int, field string) (any, error) {
+	idx := t.fieldIndex(field)
+	if idx == -1 {
+		return nil, fmt.Errorf("column %q not found", field)
+	}
+	if rowIdx < 0 || rowIdx >= len(t.rows) {
+		return nil, fmt.Errorf("row index %d out of range", rowIdx)
+	}
+	if idx >= len(t.rows[rowIdx]) {
+		return nil, nil
+	}
+	return t.rows[rowIdx][idx], nil
+}
+
+// SetCellAt sets the value of field in the stored row at rowIdx.
+func (t *Table) SetCellAt(rowIdx int, field string, val any) error {
+	if t.readonly {
+		return ErrReadOnly
+	}
+	idx := t.fieldIndex(field)
+	if idx == -1 {
+		return fmt.Errorf("column %q not found", field)
+	}
+	if rowIdx < 0 || rowIdx >= len(t.rows) {
+		return fmt.Errorf("row index %d out of range", rowIdx)
+	}
+	row := t.rows[rowIdx]
+	for len(row) <= idx {
+		row = append(row, nil)
+	}
+	old := row[idx]
+	row[idx] = val
+	t.rows[rowIdx] = row
+	t.emit(TableEvent{Type: CellUpdated, RowIndex: rowIdx, Field: field, OldValue: old, NewValue: val})
+	return nil
+}
+
+// InsertRow inserts a row before the row at the given index.
+// An index equal to the row count appends the row like AddRow.
+func (t *Table) InsertRow(index int, row []any) error {
+	if t.readonly {
+		return ErrReadOnly
+	}
+	row = t.withNumberCell(row)
+	if len(t.fieldNames) > 0 && len(row) != len(t.fieldNames) {
+		return fmt.Errorf("row has %d columns, expected %d", len(row), len(t.fieldNames))
+	}
+	if index < 0 || index > len(t.rows) {
+		return fmt.Errorf("row index %d out of range", index)
+	}
+	t.rows = append(t.rows[:index], append([][]any{row}, t.rows[index:]...)...)
+	t.renumber()
+	t.emit(TableEvent{Type: RowAdded, RowIndex: index, NewValue: row})
 	return nil
 }
 
 // AddColumn adds a column to the table with the given field name and column data.
 func (t *Table) AddColumn(field string, column []any) error {
+	if t.readonly {
+		return ErrReadOnly
+	}
 	if len(t.rows) > 0 && len(column) != len(t.rows) {
 		return fmt.Errorf("column has %d rows, expected %d", len(column), len(t.rows))
 	}
@@ -117,203 +536,2411 @@ func (t *Table) AddColumn(field string, column []any) error {
 			t.rows[i] = append(t.rows[i], val)
 		}
 	}
+	t.emit(TableEvent{Type: ColumnsChanged, RowIndex: -1, Field: field})
+	return nil
+}
+
+// InsertColumn inserts a column before the column at the given index.
+// An index equal to the column count appends the column like AddColumn.
+func (t *Table) InsertColumn(index int, field string, data []any) error {
+	if t.readonly {
+		return ErrReadOnly
+	}
+	if index < 0 || index > len(t.fieldNames) {
+		return fmt.Errorf("column index %d out of range", index)
+	}
+	if len(t.rows) > 0 && len(data) != len(t.rows) {
+		return fmt.Errorf("column has %d rows, expected %d", len(data), len(t.rows))
+	}
+	t.fieldNames = append(t.fieldNames[:index], append([]string{field}, t.fieldNames[index:]...)...)
+	if len(t.rows) == 0 {
+		for _, val := range data {
+			t.rows = append(t.rows, []any{val})
+		}
+	} else {
+		for i, val := range data {
+			row := t.rows[i]
+			at := min(index, len(row))
+			t.rows[i] = append(row[:at], append([]any{val}, row[at:]...)...)
+		}
+	}
+	t.emit(TableEvent{Type: ColumnsChanged, RowIndex: -1, Field: field})
 	return nil
 }
 
 // DelRow deletes a row at the given index.
 func (t *Table) DelRow(index int) error {
+	if t.readonly {
+		return ErrReadOnly
+	}
 	if index < 0 || index >= len(t.rows) {
 		return fmt.Errorf("row index %d out of range", index)
 	}
+	old := t.rows[index]
 	t.rows = append(t.rows[:index], t.rows[index+1:]...)
+	t.renumber()
+	t.emit(TableEvent{Type: RowDeleted, RowIndex: index, OldValue: old})
 	return nil
 }
 
 // DelColumn deletes a column by field name.
 func (t *Table) DelColumn(field string) error {
-	idx := -1
-	for i, name := range t.fieldNames {
-		if name == field {
-			idx = i
-			break
-		}
+	if t.readonly {
+		return ErrReadOnly
 	}
+	idx := t.fieldIndex(field)
 	if idx == -1 {
 		return fmt.Errorf("column %q not found", field)
 	}
 	t.fieldNames = append(t.fieldNames[:idx], t.fieldNames[idx+1:]...)
+	if field == t.autoNumberField {
+		t.autoNumberField = ""
+	}
 	for i := range t.rows {
 		if idx < len(t.rows[i]) {
 			t.rows[i] = append(t.rows[i][:idx], t.rows[i][idx+1:]...)
 		}
 	}
+	t.emit(TableEvent{Type: ColumnsChanged, RowIndex: -1, Field: field})
 	return nil
 }
 
-// ClearRows deletes all rows but keeps field names.
-func (t *Table) ClearRows() {
-	t.rows = nil
+// DropColumns returns a new table without the given columns, leaving t unchanged.
+// All unknown field names are reported in a single error.
+func (t *Table) DropColumns(fields []string) (*Table, error) {
+	drop := make(map[int]bool, len(fields))
+	var unknown []string
+	for _, f := range fields {
+		idx := t.fieldIndex(f)
+		if idx == -1 {
+			unknown = append(unknown, fmt.Sprintf("%q", f))
+			continue
+		}
+		drop[idx] = true
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("columns not found: %s", strings.Join(unknown, ", "))
+	}
+	d := t.derive(nil)
+	d.fieldNames = nil
+	for i, name := range t.fieldNames {
+		if drop[i] {
+			delete(d.alignments, name)
+			continue
+		}
+		d.fieldNames = append(d.fieldNames, name)
+	}
+	for _, row := range t.rows {
+		var kept []any
+		for i, cell := range row {
+			if !drop[i] {
+				kept = append(kept, cell)
+			}
+		}
+		d.rows = append(d.rows, kept)
+	}
+	return d, nil
 }
 
-// Clear deletes all rows and field names.
-func (t *Table) Clear() {
-	t.rows = nil
-	t.fieldNames = nil
+// RenameColumn renames a column, carrying over its alignment, sort setting
+// and custom formatter.
+func (t *Table) RenameColumn(oldName, newName string) error {
+	if t.readonly {
+		return ErrReadOnly
+	}
+	idx := t.fieldIndex(oldName)
+	if idx == -1 {
+		return fmt.Errorf("column %q not found", oldName)
+	}
+	if oldName == newName {
+		return nil
+	}
+	if t.fieldIndex(newName) != -1 {
+		return fmt.Errorf("column %q already exists", newName)
+	}
+	t.fieldNames[idx] = newName
+	if t.sortBy == oldName {
+		t.sortBy = newName
+	}
+	if t.autoNumberField == oldName {
+		t.autoNumberField = newName
+	}
+	for i := range t.sortKeys {
+		if t.sortKeys[i].Field == oldName {
+			t.sortKeys[i].Field = newName
+		}
+	}
+	renameKey(t.alignments, oldName, newName)
+	renameKey(t.headerAlignments, oldName, newName)
+	renameKey(t.headerLines, oldName, newName)
+	renameKey(t.cellHighlights, oldName, newName)
+	renameKey(t.customLess, oldName, newName)
+	renameKey(t.fixedWidths, oldName, newName)
+	renameKey(t.maxWidths, oldName, newName)
+	renameKey(t.wrapWidths, oldName, newName)
+	renameKey(t.columnPadding, oldName, newName)
+	renameKey(t.columnBoolStrings, oldName, newName)
+	renameKey(t.columnFormats, oldName, newName)
+	renameKey(t.timeLayouts, oldName, newName)
+	renameKey(t.encodings, oldName, newName)
+	renameKey(t.style.CustomFormat, oldName, newName)
+	renameKey(t.defaults, oldName, newName)
+	renameKey(t.required, oldName, newName)
+	t.emit(TableEvent{Type: ColumnsChanged, RowIndex: -1, Field: newName, OldValue: oldName, NewValue: newName})
+	return nil
 }
 
-// String renders the table as ASCII (implements fmt.Stringer)
-func (t *Table) String() string {
-	return t.RenderASCII()
+// renameKey moves the value stored under oldKey, if any, to newKey
+func renameKey[V any](m map[string]V, oldKey, newKey string) {
+	if v, ok := m[oldKey]; ok {
+		delete(m, oldKey)
+		m[newKey] = v
+	}
 }
 
-// SetAlign sets the alignment for a column by field name.
-func (t *Table) SetAlign(field string, align Alignment) {
-	if t.alignments == nil {
-		t.alignments = make(map[string]Alignment)
+// ReorderColumns rearranges the columns into the given order.
+// fields must be a permutation of the existing field names.
+func (t *Table) ReorderColumns(fields []string) error {
+	if t.readonly {
+		return ErrReadOnly
 	}
-	t.alignments[field] = align
+	seen := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if seen[f] {
+			return fmt.Errorf("column %q listed more than once", f)
+		}
+		seen[f] = true
+	}
+	if len(fields) != len(t.fieldNames) {
+		return fmt.Errorf("got %d columns, expected all %d", len(fields), len(t.fieldNames))
+	}
+	order := make([]int, len(fields))
+	for i, f := range fields {
+		idx := t.fieldIndex(f)
+		if idx == -1 {
+			return fmt.Errorf("column %q not found", f)
+		}
+		order[i] = idx
+	}
+	for i, row := range t.rows {
+		reordered := make([]any, len(row))
+		for j, idx := range order {
+			if idx < len(row) {
+				reordered[j] = row[idx]
+			}
+		}
+		t.rows[i] = reordered
+	}
+	t.fieldNames = append([]string(nil), fields...)
+	t.emit(TableEvent{Type: ColumnsChanged, RowIndex: -1})
+	return nil
 }
 
-// SetAlignAll sets the alignment for all columns.
-func (t *Table) SetAlignAll(align Alignment) {
-	if t.alignments == nil {
-		t.alignments = make(map[string]Alignment)
+// SwapRows exchanges the rows at indices i and j.
+func (t *Table) SwapRows(i, j int) error {
+	if t.readonly {
+		return ErrReadOnly
 	}
-	for _, f := range t.fieldNames {
-		t.alignments[f] = align
+	for _, idx := range []int{i, j} {
+		if idx < 0 || idx >= len(t.rows) {
+			return fmt.Errorf("row index %d out of range", idx)
+		}
 	}
+	t.rows[i], t.rows[j] = t.rows[j], t.rows[i]
+	t.renumber()
+	t.emit(TableEvent{Type: RowUpdated, RowIndex: i, OldValue: t.rows[j], NewValue: t.rows[i]})
+	t.emit(TableEvent{Type: RowUpdated, RowIndex: j, OldValue: t.rows[i], NewValue: t.rows[j]})
+	return nil
 }
 
-// SetSortBy sets the field to sort by and order.
-func (t *Table) SetSortBy(field string, reverse bool) {
-	t.sortBy = field
-	t.reverseSort = reverse
+// Reverse reverses the order of the stored rows in place.
+func (t *Table) Reverse() {
+	if t.readonly {
+		return
+	}
+	for i, j := 0, len(t.rows)-1; i < j; i, j = i+1, j-1 {
+		t.rows[i], t.rows[j] = t.rows[j], t.rows[i]
+	}
+	t.renumber()
+	t.emit(TableEvent{Type: RowUpdated, RowIndex: -1})
 }
 
-// SetRowFilter sets a filter function for rows.
-func (t *Table) SetRowFilter(filter func([]any) bool) {
-	t.rowFilter = filter
+// ReversedCopy returns a new table with the stored rows in reverse order,
+// leaving t unchanged. Sort and filter settings are not copied.
+func (t *Table) ReversedCopy() *Table {
+	d := t.derive(t.rows)
+	d.Reverse()
+	return d
 }
 
-// SetStyle sets the table style options
-func (t *Table) SetStyle(style TableStyle) {
-	t.style = style
+// SwapColumns exchanges the columns named a and b.
+func (t *Table) SwapColumns(a, b string) error {
+	if t.readonly {
+		return ErrReadOnly
+	}
+	ia := t.fieldIndex(a)
+	if ia == -1 {
+		return fmt.Errorf("column %q not found", a)
+	}
+	ib := t.fieldIndex(b)
+	if ib == -1 {
+		return fmt.Errorf("column %q not found", b)
+	}
+	if ia == ib {
+		return nil
+	}
+	t.fieldNames[ia], t.fieldNames[ib] = t.fieldNames[ib], t.fieldNames[ia]
+	for _, row := range t.rows {
+		if ia < len(row) && ib < len(row) {
+			row[ia], row[ib] = row[ib], row[ia]
+		}
+	}
+	t.emit(TableEvent{Type: ColumnsChanged, RowIndex: -1})
+	return nil
 }
 
-// RenderASCII renders the table as an ASCII string
-func (t *Table) RenderASCII() string {
-	if len(t.fieldNames) == 0 {
-		return "(no fields)"
+// FillNil replaces nil cells in a column with replacement and returns how
+// many cells were replaced.
+func (t *Table) FillNil(field string, replacement any) (int, error) {
+	if t.readonly {
+		return 0, ErrReadOnly
 	}
-	// Compute column widths
-	colWidths := make([]int, len(t.fieldNames))
-	for i, name := range t.fieldNames {
-		colWidths[i] = len(name)
+	idx := t.fieldIndex(field)
+	if idx == -1 {
+		return 0, fmt.Errorf("column %q not found", field)
 	}
-	rows := t.rows
-	// Filtering
-	if t.rowFilter != nil {
-		var filtered [][]any
-		for _, row := range rows {
-			if t.rowFilter(row) {
-				filtered = append(filtered, row)
-			}
+	n := 0
+	for r, row := range t.rows {
+		if idx < len(row) && row[idx] == nil {
+			row[idx] = replacement
+			n++
+			t.emit(TableEvent{Type: CellUpdated, RowIndex: r, Field: field, NewValue: replacement})
 		}
-		rows = filtered
 	}
-	// Sorting
-	if t.sortBy != "" {
-		idx := -1
-		for i, name := range t.fieldNames {
-			if name == t.sortBy {
-				idx = i
-				break
-			}
-		}
-		if idx != -1 {
-			sorted := make([][]any, len(rows))
-			copy(sorted, rows)
-			less := func(i, j int) bool {
-				si := fmt.Sprintf("%v", sorted[i][idx])
-				sj := fmt.Sprintf("%v", sorted[j][idx])
-				if t.reverseSort {
-					return sj < si
+	return n, nil
+}
+
+// FillAllNils replaces nil cells in every column with replacement and returns
+// how many cells were replaced.
+func (t *Table) FillAllNils(replacement any) int {
+	if t.readonly {
+		return 0
+	}
+	n := 0
+	for r, row := range t.rows {
+		for i, cell := range row {
+			if cell == nil {
+				row[i] = replacement
+				n++
+				if i < len(t.fieldNames) {
+					t.emit(TableEvent{Type: CellUpdated, RowIndex: r, Field: t.fieldNames[i], NewValue: replacement})
 				}
-				return si < sj
 			}
-			sort.Slice(sorted, less)
-			rows = sorted
 		}
 	}
-	for i, name := range t.fieldNames {
-		colWidths[i] = len(name)
+	return n
+}
+
+// MapColumn replaces every cell in a column with the result of fn applied to it.
+func (t *Table) MapColumn(field string, fn func(any) any) error {
+	if t.readonly {
+		return ErrReadOnly
 	}
-	for _, row := range rows {
-		for i, cell := range row {
-			cellStr := fmt.Sprintf("%v", cell)
-			if len(cellStr) > colWidths[i] {
-				colWidths[i] = len(cellStr)
-			}
-		}
+	idx := t.fieldIndex(field)
+	if idx == -1 {
+		return fmt.Errorf("column %q not found", field)
 	}
-	// Helper to build a line
-	line := func(sep, fill string) string {
-		var b strings.Builder
-		b.WriteString(sep)
-		for i, w := range colWidths {
-			b.WriteString(strings.Repeat(fill, w+2))
-			b.WriteString(sep)
-			if i == len(colWidths)-1 {
-				break
-			}
+	for r, row := range t.rows {
+		if idx < len(row) {
+			old := row[idx]
+			row[idx] = fn(old)
+			t.emit(TableEvent{Type: CellUpdated, RowIndex: r, Field: field, OldValue: old, NewValue: row[idx]})
 		}
-		return b.String()
 	}
-	// Build table
-	var b strings.Builder
-	b.WriteString(line("+", "-"))
-	b.WriteString("\n")
-	// Header
-	b.WriteString("|")
-	for i, name := range t.fieldNames {
-		align := AlignLeft
-		if t.alignments != nil {
-			if a, ok := t.alignments[name]; ok {
-				align = a
-			}
-		}
-		b.WriteString(" ")
-		b.WriteString(padAlign(name, colWidths[i], align))
-		b.WriteString(" |")
-		if i == len(t.fieldNames)-1 {
+	return nil
+}
+
+// MapColumnCopy is like MapColumn but returns a new table, leaving t unchanged.
+func (t *Table) MapColumnCopy(field string, fn func(any) any) (*Table, error) {
+	d := t.derive(t.rows)
+	if err := d.MapColumn(field, fn); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// ColumnIndex returns the zero-based index of field, or -1 if there is no
+// such column
+func (t *Table) ColumnIndex(field string) int {
+	return t.fieldIndex(field)
+}
+
+// HasColumn reports whether the table has a column named field
+func (t *Table) HasColumn(field string) bool {
+	return t.fieldIndex(field) != -1
+}
+
+// fieldIndex returns the index of field in the field names, or -1 if absent
+func (t *Table) fieldIndex(field string) int {
+	for i, name := range t.fieldNames {
+		if name == field {
+			return i
+		}
+	}
+	return -1
+}
+
+// ClearRows deletes all rows but keeps field names.
+func (t *Table) ClearRows() error {
+	if t.readonly {
+		return ErrReadOnly
+	}
+	t.rows = nil
+	t.emit(TableEvent{Type: Cleared, RowIndex: -1})
+	return nil
+}
+
+// Clear deletes all rows and field names.
+func (t *Table) Clear() error {
+	if t.readonly {
+		return ErrReadOnly
+	}
+	t.rows = nil
+	t.fieldNames = nil
+	t.autoNumberField = ""
+	t.emit(TableEvent{Type: Cleared, RowIndex: -1})
+	return nil
+}
+
+// Snapshot returns a read-only copy of the table as it is now, including its
+// sort, filter and presentation settings. Methods that change the rows or
+// columns of the snapshot return ErrReadOnly; Reverse and FillAllNils do
+// nothing. Rendering and other read methods work normally.
+func (t *Table) Snapshot() *Table {
+	d := t.derive(t.rows)
+	d.style.CustomFormat = maps.Clone(t.style.CustomFormat)
+	d.sortBy = t.sortBy
+	d.reverseSort = t.reverseSort
+	d.sortKeys = append([]SortKey(nil), t.sortKeys...)
+	d.customLess = maps.Clone(t.customLess)
+	d.sortLocale = t.sortLocale
+	d.rowFilter = t.rowFilter
+	d.rowNumberLabel = t.rowNumberLabel
+	d.autoNumberField = t.autoNumberField
+	d.autoNumberStart = t.autoNumberStart
+	d.highlights = maps.Clone(t.highlights)
+	d.cellHighlights = maps.Clone(t.cellHighlights)
+	d.defaults = maps.Clone(t.defaults)
+	d.required = maps.Clone(t.required)
+	d.dataSeparator = t.dataSeparator
+	d.groupSep = t.groupSep
+	d.groupSepStyle = t.groupSepStyle
+	d.readonly = true
+	return d
+}
+
+// Head returns a new table with the first n rows in render order
+// (after filtering and sorting). If n exceeds the row count, all rows are returned.
+func (t *Table) Head(n int) *Table {
+	rows := t.displayRows()
+	n = max(0, min(n, len(rows)))
+	return t.derive(rows[:n])
+}
+
+// Tail returns a new table with the last n rows in render order
+// (after filtering and sorting). If n exceeds the row count, all rows are returned.
+func (t *Table) Tail(n int) *Table {
+	rows := t.displayRows()
+	n = max(0, min(n, len(rows)))
+	return t.derive(rows[len(rows)-n:])
+}
+
+// TopN returns a new table with the n rows that have the largest values in
+// field, largest first. Numbers compare by value and other cells as with
+// SetSortBy; a comparison registered with SortByCustom takes precedence.
+// The row filter applies but the table's own sort setting does not, and the
+// table is not modified.
+func (t *Table) TopN(n int, field string) (*Table, error) {
+	return t.firstNBy(n, field, true)
+}
+
+// BottomN returns a new table with the n rows that have the smallest values
+// in field, smallest first. See TopN.
+func (t *Table) BottomN(n int, field string) (*Table, error) {
+	return t.firstNBy(n, field, false)
+}
+
+func (t *Table) firstNBy(n int, field string, reverse bool) (*Table, error) {
+	if t.fieldIndex(field) == -1 {
+		return nil, fmt.Errorf("column %q not found", field)
+	}
+	less, ok := t.customLess[field]
+	if !ok {
+		strLess := t.stringLess()
+		less = func(a, b any) bool {
+			fa, okA := toFloat(a)
+			fb, okB := toFloat(b)
+			if okA && okB {
+				return fa < fb
+			}
+			return strLess(a, b)
+		}
+	}
+	sorted := &Table{
+		fieldNames: t.fieldNames,
+		rows:       t.rows,
+		rowFilter:  t.rowFilter,
+		sortKeys:   []SortKey{{Field: field, Reverse: reverse, Less: less}},
+	}
+	rows := sorted.displayRows()
+	n = max(0, min(n, len(rows)))
+	return t.derive(rows[:n]), nil
+}
+
+// Sample returns a new table with n rows picked at random, without
+// replacement, from all stored rows. The same seed always yields the same
+// sample. If n exceeds the row count, all rows are returned in shuffled order.
+func (t *Table) Sample(n int, seed int64) (*Table, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("sample size must be positive, got %d", n)
+	}
+	r := rand.New(rand.NewSource(seed))
+	perm := r.Perm(len(t.rows))
+	n = min(n, len(perm))
+	rows := make([][]any, n)
+	for i, idx := range perm[:n] {
+		rows[i] = t.rows[idx]
+	}
+	return t.derive(rows), nil
+}
+
+// FindRow returns the first row in render order (after filtering and sorting)
+// for which fn returns true, along with its display index.
+// The last return value is false if no row matches.
+func (t *Table) FindRow(fn func([]any) bool) (int, []any, bool) {
+	for i, row := range t.displayRows() {
+		if fn(row) {
+			return i, append([]any(nil), row...), true
+		}
+	}
+	return -1, nil, false
+}
+
+// FindAllRows returns all rows in render order for which fn returns true.
+func (t *Table) FindAllRows(fn func([]any) bool) [][]any {
+	var matches [][]any
+	for _, row := range t.displayRows() {
+		if fn(row) {
+			matches = append(matches, row)
+		}
+	}
+	return copyRows(matches)
+}
+
+// Reduce folds the rows in render order, with the row filter and sorting
+// applied, calling fn with the running accumulator and each row. It returns
+// the final accumulator, or initial if there are no rows.
+func (t *Table) Reduce(initial any, fn func(acc any, row []any) any) any {
+	acc := initial
+	for _, row := range t.displayRows() {
+		acc = fn(acc, row)
+	}
+	return acc
+}
+
+// RenderDiff renders a table of the stored rows of t and other, each
+// prefixed by "-" if it is only in t, "+" if it is only in other, or " " if
+// it is in both. Rows match when all their values are equal as strings and
+// keep their relative order; a changed row shows as a deletion followed by an
+// insertion. Both tables must have the same field names. If ANSIEnabled is
+// set in the style of t, removed rows are red and added rows green.
+func (t *Table) RenderDiff(other *Table) string {
+	return renderToString(func(w io.Writer) error { return t.RenderDiffToWriter(w, other) })
+}
+
+// RenderDiffToWriter writes the output of RenderDiff to w
+func (t *Table) RenderDiffToWriter(w io.Writer, other *Table) error {
+	if !slices.Equal(t.fieldNames, other.fieldNames) {
+		return fmt.Errorf("cannot diff tables with fields %q and %q", t.fieldNames, other.fieldNames)
+	}
+	if len(t.fieldNames) == 0 {
+		_, err := io.WriteString(w, "(no fields)")
+		return err
+	}
+	a, b := t.rows, other.rows
+	keysA := make([]string, len(a))
+	for i, row := range a {
+		keysA[i] = rowKey(row, nil)
+	}
+	keysB := make([]string, len(b))
+	for i, row := range b {
+		keysB[i] = rowKey(row, nil)
+	}
+	// lcs[i][j] is the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if keysA[i] == keysB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var rows [][]any
+	add := func(prefix string, row []any) {
+		rows = append(rows, append([]any{prefix}, row...))
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && keysA[i] == keysB[j]:
+			add(" ", a[i])
+			i, j = i+1, j+1
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			add("-", a[i])
+			i++
+		default:
+			add("+", b[j])
+			j++
+		}
+	}
+	d := t.derive(nil)
+	d.fieldNames = append([]string{""}, d.fieldNames...)
+	d.rows = rows
+	d.SetConditionalRowStyle(func(_ int, row []any) RowStyle {
+		switch row[0] {
+		case "-":
+			return RowStyle{FG: ColorRed}
+		case "+":
+			return RowStyle{FG: ColorGreen}
+		}
+		return RowStyle{}
+	})
+	if d.style.ANSIEnabled {
+		return d.RenderANSIToWriter(w)
+	}
+	return d.RenderASCIIToWriter(w)
+}
+
+// Partition splits the stored rows in one pass into a table of the rows for
+// which fn returns true and a table of the rest. Both keep the field names,
+// alignments and style of t.
+func (t *Table) Partition(fn func([]any) bool) (matching *Table, nonMatching *Table) {
+	var yes, no [][]any
+	for _, row := range t.rows {
+		if fn(row) {
+			yes = append(yes, row)
+		} else {
+			no = append(no, row)
+		}
+	}
+	return t.derive(yes), t.derive(no)
+}
+
+// ExplodeColumn returns a new table with one row per element of the slice
+// cells of field, the other cells of the row being repeated. An empty slice
+// gives a single row with a nil cell, []byte is kept whole, and cells that
+// are not slices are kept as they are.
+func (t *Table) ExplodeColumn(field string) (*Table, error) {
+	idx := t.fieldIndex(field)
+	if idx == -1 {
+		return nil, fmt.Errorf("column %q not found", field)
+	}
+	var rows [][]any
+	for _, row := range t.rows {
+		if idx >= len(row) {
+			rows = append(rows, row)
+			continue
+		}
+		elems := []any{row[idx]}
+		if v := reflect.ValueOf(row[idx]); v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+			elems = make([]any, v.Len())
+			for i := range elems {
+				elems[i] = v.Index(i).Interface()
+			}
+			if len(elems) == 0 {
+				elems = []any{nil}
+			}
+		}
+		for _, e := range elems {
+			exploded := slices.Clone(row)
+			exploded[idx] = e
+			rows = append(rows, exploded)
+		}
+	}
+	return t.derive(rows), nil
+}
+
+// ValidationError describes a cell rejected by ValidateRows
+type ValidationError struct {
+	RowIndex int
+	Field    string
+	Value    any
+	Err      error
+}
+
+// Error implements the error interface
+func (e ValidationError) Error() string {
+	if e.RowIndex < 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("row %d, column %q: %v", e.RowIndex, e.Field, e.Err)
+}
+
+// Unwrap returns the validator's error
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateRows runs each validator against every stored cell of its column
+// and collects all failures, ordered by row and then by column. A validator
+// for an unknown column is reported once with a RowIndex of -1.
+func (t *Table) ValidateRows(validators map[string]func(any) error) []ValidationError {
+	var errs []ValidationError
+	var idxs []int
+	for field := range validators {
+		idx := t.fieldIndex(field)
+		if idx == -1 {
+			errs = append(errs, ValidationError{RowIndex: -1, Field: field, Err: fmt.Errorf("column %q not found", field)})
+			continue
+		}
+		idxs = append(idxs, idx)
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	sort.Ints(idxs)
+	for r, row := range t.rows {
+		for _, idx := range idxs {
+			var v any
+			if idx < len(row) {
+				v = row[idx]
+			}
+			field := t.fieldNames[idx]
+			if err := validators[field](v); err != nil {
+				errs = append(errs, ValidationError{RowIndex: r, Field: field, Value: v, Err: err})
+			}
+		}
+	}
+	return errs
+}
+
+// SetColumnRequired marks field as one that must not hold nil cells; see
+// ValidateRequired.
+func (t *Table) SetColumnRequired(field string) {
+	if t.required == nil {
+		t.required = make(map[string]bool)
+	}
+	t.required[field] = true
+}
+
+// ValidateRequired reports every nil cell in a column marked with
+// SetColumnRequired. Each error is a ValidationError wrapping ErrRequired,
+// ordered by row and then by column.
+func (t *Table) ValidateRequired() []error {
+	validators := make(map[string]func(any) error)
+	for field := range t.required {
+		validators[field] = func(v any) error {
+			if v == nil {
+				return ErrRequired
+			}
+			return nil
+		}
+	}
+	var errs []error
+	for _, e := range t.ValidateRows(validators) {
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// DeduplicateStats reports how many rows a deduplication kept and removed
+type DeduplicateStats struct {
+	Kept    int
+	Removed int
+}
+
+// Deduplicate returns a new table without duplicate rows, keeping the first
+// occurrence. Rows are compared on keyFields, or on all columns if none are
+// given, using the %v representation of each value. If a key field does not
+// exist, no rows are removed; DeduplicateWithStats reports the error.
+func (t *Table) Deduplicate(keyFields ...string) *Table {
+	d, _, err := t.DeduplicateWithStats(keyFields...)
+	if err != nil {
+		return t.derive(t.rows)
+	}
+	return d
+}
+
+// DeduplicateWithStats is like Deduplicate but also reports how many rows
+// were removed. Unknown key fields are an error.
+func (t *Table) DeduplicateWithStats(keyFields ...string) (*Table, DeduplicateStats, error) {
+	var idxs []int
+	for _, f := range keyFields {
+		idx := t.fieldIndex(f)
+		if idx == -1 {
+			return nil, DeduplicateStats{}, fmt.Errorf("column %q not found", f)
+		}
+		idxs = append(idxs, idx)
+	}
+	seen := make(map[string]bool)
+	var kept [][]any
+	for _, row := range t.rows {
+		key := rowKey(row, idxs)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		kept = append(kept, row)
+	}
+	stats := DeduplicateStats{Kept: len(kept), Removed: len(t.rows) - len(kept)}
+	return t.derive(kept), stats, nil
+}
+
+// UniqueValues returns the distinct values of a column in first-seen order.
+// All stored rows are considered, regardless of the row filter.
+func (t *Table) UniqueValues(field string) ([]any, error) {
+	idx := t.fieldIndex(field)
+	if idx == -1 {
+		return nil, fmt.Errorf("column %q not found", field)
+	}
+	seen := make(map[string]bool)
+	var values []any
+	for _, row := range t.rows {
+		if idx >= len(row) {
+			continue
+		}
+		key := fmt.Sprintf("%v", row[idx])
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		values = append(values, row[idx])
+	}
+	return values, nil
+}
+
+// UniqueCount returns the number of distinct values in a column.
+func (t *Table) UniqueCount(field string) (int, error) {
+	values, err := t.UniqueValues(field)
+	if err != nil {
+		return 0, err
+	}
+	return len(values), nil
+}
+
+// Chain returns a new table with the stored rows of all tables in order.
+// Every table must have the same field names in the same order; otherwise
+// the error lists each mismatching table. The result keeps the alignments
+// and style of the first table.
+func Chain(tables ...*Table) (*Table, error) {
+	if len(tables) == 0 {
+		return nil, errors.New("no tables to chain")
+	}
+	var errs []error
+	var rows [][]any
+	for i, tbl := range tables {
+		if !slices.Equal(tbl.fieldNames, tables[0].fieldNames) {
+			errs = append(errs, fmt.Errorf("table %d has fields %q, expected %q", i, tbl.fieldNames, tables[0].fieldNames))
+			continue
+		}
+		rows = append(rows, tbl.rows...)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return tables[0].derive(rows), nil
+}
+
+// JoinOptions controls how Join combines two tables
+type JoinOptions struct {
+	// Left keeps left rows without a match, with nil in the right columns
+	Left bool
+	// CollisionPrefix is prepended to right field names that already exist
+	// in the left table. The default is "right_".
+	CollisionPrefix string
+}
+
+// InnerJoin returns a new table with the rows of t and other whose leftField
+// and rightField values are equal, compared by their %v representation.
+func (t *Table) InnerJoin(other *Table, leftField, rightField string) (*Table, error) {
+	return t.Join(other, leftField, rightField, JoinOptions{})
+}
+
+// LeftJoin is like InnerJoin but also keeps rows of t without a match,
+// with nil in the columns of other.
+func (t *Table) LeftJoin(other *Table, leftField, rightField string) (*Table, error) {
+	return t.Join(other, leftField, rightField, JoinOptions{Left: true})
+}
+
+// Join returns a new table joining the stored rows of t and other on
+// leftField and rightField. The result has all columns of t followed by
+// those of other except rightField, and keeps the alignments and style of t.
+func (t *Table) Join(other *Table, leftField, rightField string, opts JoinOptions) (*Table, error) {
+	li := t.fieldIndex(leftField)
+	if li == -1 {
+		return nil, fmt.Errorf("column %q not found", leftField)
+	}
+	ri := other.fieldIndex(rightField)
+	if ri == -1 {
+		return nil, fmt.Errorf("column %q not found", rightField)
+	}
+	prefix := opts.CollisionPrefix
+	if prefix == "" {
+		prefix = "right_"
+	}
+	d := t.derive(nil)
+	var rightCols []int
+	for i, name := range other.fieldNames {
+		if i == ri {
+			continue
+		}
+		newName := name
+		if d.fieldIndex(newName) != -1 {
+			newName = prefix + name
+			if d.fieldIndex(newName) != -1 {
+				return nil, fmt.Errorf("column %q already exists", newName)
+			}
+		}
+		d.fieldNames = append(d.fieldNames, newName)
+		if a, ok := other.alignments[name]; ok {
+			d.SetAlign(newName, a)
+		}
+		rightCols = append(rightCols, i)
+	}
+	matches := make(map[string][][]any)
+	for _, row := range other.rows {
+		key := rowKey(row, []int{ri})
+		matches[key] = append(matches[key], row)
+	}
+	for _, row := range t.rows {
+		rightRows := matches[rowKey(row, []int{li})]
+		if len(rightRows) == 0 && opts.Left {
+			rightRows = [][]any{nil}
+		}
+		for _, right := range rightRows {
+			joined := append([]any(nil), row...)
+			for _, i := range rightCols {
+				var v any
+				if i < len(right) {
+					v = right[i]
+				}
+				joined = append(joined, v)
+			}
+			d.rows = append(d.rows, joined)
+		}
+	}
+	return d, nil
+}
+
+// Zip returns a new table interleaving the stored rows of t and other: row 0
+// of t, row 0 of other, row 1 of t, and so on, with the rows left over in
+// the longer table at the end. The result has the columns of t followed by
+// those of other, whose names are prefixed with "right_" if they already
+// exist in t. Each row has nil in the columns of the other table.
+func (t *Table) Zip(other *Table) (*Table, error) {
+	d := t.derive(nil)
+	for _, name := range other.fieldNames {
+		newName := name
+		if d.fieldIndex(newName) != -1 {
+			newName = "right_" + name
+			if d.fieldIndex(newName) != -1 {
+				return nil, fmt.Errorf("column %q already exists", newName)
+			}
+		}
+		d.fieldNames = append(d.fieldNames, newName)
+		if a, ok := other.alignments[name]; ok {
+			d.SetAlign(newName, a)
+		}
+	}
+	left, right := len(t.fieldNames), len(other.fieldNames)
+	for i := range max(len(t.rows), len(other.rows)) {
+		if i < len(t.rows) {
+			row := make([]any, left+right)
+			copy(row, t.rows[i])
+			d.rows = append(d.rows, row)
+		}
+		if i < len(other.rows) {
+			row := make([]any, left+right)
+			copy(row[left:], other.rows[i])
+			d.rows = append(d.rows, row)
+		}
+	}
+	return d, nil
+}
+
+// AggFunc selects how Pivot and GroupByStats combine a group of values
+type AggFunc int
+
+const (
+	AggSum AggFunc = iota
+	AggMean
+	AggCount
+	AggFirst
+	AggLast
+	AggMin
+	AggMax
+)
+
+// String returns the lower-case name of the aggregate function
+func (agg AggFunc) String() string {
+	switch agg {
+	case AggSum:
+		return "sum"
+	case AggMean:
+		return "mean"
+	case AggCount:
+		return "count"
+	case AggFirst:
+		return "first"
+	case AggLast:
+		return "last"
+	case AggMin:
+		return "min"
+	case AggMax:
+		return "max"
+	}
+	return fmt.Sprintf("AggFunc(%d)", int(agg))
+}
+
+// apply aggregates values, skipping nils. Sum, mean, min and max require
+// numeric values; sum and mean return a float64 while min and max return the
+// original value. All but count yield nil when there are no values.
+func (agg AggFunc) apply(field string, values []any) (any, error) {
+	var present []any
+	for _, v := range values {
+		if v != nil {
+			present = append(present, v)
+		}
+	}
+	switch agg {
+	case AggCount:
+		return len(present), nil
+	case AggFirst, AggLast:
+		if len(present) == 0 {
+			return nil, nil
+		}
+		if agg == AggFirst {
+			return present[0], nil
+		}
+		return present[len(present)-1], nil
+	case AggSum, AggMean, AggMin, AggMax:
+		if len(present) == 0 {
+			return nil, nil
+		}
+		sum := 0.0
+		var best any
+		var bestF float64
+		for i, v := range present {
+			f, ok := toFloat(v)
+			if !ok {
+				return nil, fmt.Errorf("value %v in column %q is not numeric", v, field)
+			}
+			sum += f
+			if i == 0 || (agg == AggMin && f < bestF) || (agg == AggMax && f > bestF) {
+				best, bestF = v, f
+			}
+		}
+		switch agg {
+		case AggMean:
+			return sum / float64(len(present)), nil
+		case AggMin, AggMax:
+			return best, nil
+		}
+		return sum, nil
+	}
+	return nil, fmt.Errorf("unknown aggregate function %d", agg)
+}
+
+// toFloat converts a numeric cell to float64
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// Pivot returns a cross-tabulation of the stored rows. The result has
+// rowField as its first column followed by one column per distinct value of
+// colField, both in first-seen order, and each cell aggregates the valueField
+// values of the matching rows. Cells without matching rows are nil.
+func (t *Table) Pivot(rowField, colField, valueField string, agg AggFunc) (*Table, error) {
+	idxs := make([]int, 3)
+	for i, f := range []string{rowField, colField, valueField} {
+		if idxs[i] = t.fieldIndex(f); idxs[i] == -1 {
+			return nil, fmt.Errorf("column %q not found", f)
+		}
+	}
+	ri, ci, vi := idxs[0], idxs[1], idxs[2]
+	fields := []string{rowField}
+	var rowVals []any
+	rowPos := make(map[string]int)
+	colPos := make(map[string]int)
+	var cells [][][]any
+	for _, row := range t.rows {
+		if max(ri, ci, vi) >= len(row) {
+			continue
+		}
+		rk := rowKey(row, []int{ri})
+		r, ok := rowPos[rk]
+		if !ok {
+			r = len(rowVals)
+			rowPos[rk] = r
+			rowVals = append(rowVals, row[ri])
+			cells = append(cells, nil)
+		}
+		name := fmt.Sprintf("%v", row[ci])
+		c, ok := colPos[name]
+		if !ok {
+			if slices.Contains(fields, name) {
+				return nil, fmt.Errorf("column %q already exists", name)
+			}
+			c = len(fields) - 1
+			colPos[name] = c
+			fields = append(fields, name)
+		}
+		for len(cells[r]) <= c {
+			cells[r] = append(cells[r], nil)
+		}
+		cells[r][c] = append(cells[r][c], row[vi])
+	}
+	p := NewTableWithFields(fields)
+	p.style = t.style
+	for r, key := range rowVals {
+		out := make([]any, len(fields))
+		out[0] = key
+		for c := range len(fields) - 1 {
+			if c >= len(cells[r]) || cells[r][c] == nil {
+				continue
+			}
+			v, err := agg.apply(valueField, cells[r][c])
+			if err != nil {
+				return nil, err
+			}
+			out[c+1] = v
+		}
+		p.rows = append(p.rows, out)
+	}
+	return p, nil
+}
+
+// ColumnStat describes one aggregated column of GroupByStats
+type ColumnStat struct {
+	Field string
+	Func  AggFunc
+	// OutputName is the result column name. The default is Field_func,
+	// e.g. "Sales_sum".
+	OutputName string
+}
+
+// GroupByStats groups the stored rows by groupField and returns a new table
+// with groupField as its first column, in first-seen order, followed by one
+// column per requested statistic.
+func (t *Table) GroupByStats(groupField string, stats []ColumnStat) (*Table, error) {
+	gi := t.fieldIndex(groupField)
+	if gi == -1 {
+		return nil, fmt.Errorf("column %q not found", groupField)
+	}
+	fields := []string{groupField}
+	idxs := make([]int, len(stats))
+	for i, st := range stats {
+		if idxs[i] = t.fieldIndex(st.Field); idxs[i] == -1 {
+			return nil, fmt.Errorf("column %q not found", st.Field)
+		}
+		name := st.OutputName
+		if name == "" {
+			name = fmt.Sprintf("%s_%s", st.Field, st.Func)
+		}
+		if slices.Contains(fields, name) {
+			return nil, fmt.Errorf("column %q already exists", name)
+		}
+		fields = append(fields, name)
+	}
+	var keys []any
+	groupPos := make(map[string]int)
+	var groups [][][]any
+	for _, row := range t.rows {
+		if gi >= len(row) {
+			continue
+		}
+		k := rowKey(row, []int{gi})
+		g, ok := groupPos[k]
+		if !ok {
+			g = len(keys)
+			groupPos[k] = g
+			keys = append(keys, row[gi])
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], row)
+	}
+	out := NewTableWithFields(fields)
+	out.style = t.style
+	for g, key := range keys {
+		row := []any{key}
+		for i, st := range stats {
+			values := make([]any, 0, len(groups[g]))
+			for _, r := range groups[g] {
+				if idxs[i] < len(r) {
+					values = append(values, r[idxs[i]])
+				}
+			}
+			v, err := st.Func.apply(st.Field, values)
+			if err != nil {
+				return nil, err
+			}
+			row = append(row, v)
+		}
+		out.rows = append(out.rows, row)
+	}
+	return out, nil
+}
+
+// rowKey builds a comparison key from the %v representation of the cells
+// at idxs, or of all cells if idxs is empty
+func rowKey(row []any, idxs []int) string {
+	var b strings.Builder
+	write := func(cell any) {
+		b.WriteString(strconv.Quote(fmt.Sprintf("%v", cell)))
+		b.WriteString(",")
+	}
+	if len(idxs) == 0 {
+		for _, cell := range row {
+			write(cell)
+		}
+		return b.String()
+	}
+	for _, idx := range idxs {
+		if idx < len(row) {
+			write(row[idx])
+		}
+	}
+	return b.String()
+}
+
+// derive returns a new table with the field names, alignments and style of t
+// holding a copy of the given rows. Sorting and filtering are not carried over.
+func (t *Table) derive(rows [][]any) *Table {
+	d := &Table{
+		fieldNames:        append([]string(nil), t.fieldNames...),
+		rows:              copyRows(rows),
+		alignments:        maps.Clone(t.alignments),
+		headerAlignments:  maps.Clone(t.headerAlignments),
+		headerLines:       maps.Clone(t.headerLines),
+		style:             t.style,
+		fixedWidths:       maps.Clone(t.fixedWidths),
+		maxWidths:         maps.Clone(t.maxWidths),
+		wrapWidths:        maps.Clone(t.wrapWidths),
+		autoWrapWidth:     t.autoWrapWidth,
+		columnPadding:     maps.Clone(t.columnPadding),
+		rowStyle:          t.rowStyle,
+		emptyMessage:      t.emptyMessage,
+		nullString:        t.nullString,
+		boolStrings:       t.boolStrings,
+		columnBoolStrings: maps.Clone(t.columnBoolStrings),
+		columnFormats:     maps.Clone(t.columnFormats),
+		timeLayouts:       maps.Clone(t.timeLayouts),
+		encodings:         maps.Clone(t.encodings),
+		caption:           t.caption,
+		captionBelow:      t.captionBelow,
+		latexLabel:        t.latexLabel,
+		comment:           t.comment,
+		csvDelimiter:      t.csvDelimiter,
+	}
+	return d
+}
+
+// copyRows returns a copy of rows that shares no slices with the original
+func copyRows(rows [][]any) [][]any {
+	if rows == nil {
+		return nil
+	}
+	out := make([][]any, len(rows))
+	for i, row := range rows {
+		out[i] = append([]any(nil), row...)
+	}
+	return out
+}
+
+// String renders the table as ASCII (implements fmt.Stringer)
+func (t *Table) String() string {
+	return t.RenderASCII()
+}
+
+// SetAlign sets the alignment for a column by field name.
+func (t *Table) SetAlign(field string, align Alignment) {
+	if t.alignments == nil {
+		t.alignments = make(map[string]Alignment)
+	}
+	t.alignments[field] = align
+}
+
+// SetMultiLineHeader shows the header of a column as several lines in
+// bordered renderers, so a long name can take less width. Single-line
+// headers of other columns are aligned to the bottom line. Passing no lines
+// restores the field name.
+func (t *Table) SetMultiLineHeader(field string, lines []string) {
+	if len(lines) == 0 {
+		delete(t.headerLines, field)
+		return
+	}
+	if t.headerLines == nil {
+		t.headerLines = make(map[string][]string)
+	}
+	t.headerLines[field] = append([]string(nil), lines...)
+}
+
+// headerText returns the header lines of column i
+func (t *Table) headerText(i int) []string {
+	if lines, ok := t.headerLines[t.fieldNames[i]]; ok {
+		return lines
+	}
+	return []string{t.fieldNames[i]}
+}
+
+// SetHeaderAlign sets the alignment of a column's header cell in bordered
+// renderers. Without it the header uses the column's data alignment.
+func (t *Table) SetHeaderAlign(field string, align Alignment) {
+	if t.headerAlignments == nil {
+		t.headerAlignments = make(map[string]Alignment)
+	}
+	t.headerAlignments[field] = align
+}
+
+// SetAlignAll sets the alignment for all columns.
+func (t *Table) SetAlignAll(align Alignment) {
+	if t.alignments == nil {
+		t.alignments = make(map[string]Alignment)
+	}
+	for _, f := range t.fieldNames {
+		t.alignments[f] = align
+	}
+}
+
+// SetAlignmentFromTypes right-aligns columns whose first non-nil stored
+// value is a number, leaving other columns left-aligned. Columns with an
+// alignment already set are not changed.
+func (t *Table) SetAlignmentFromTypes() {
+	for i, name := range t.fieldNames {
+		if _, ok := t.alignments[name]; ok {
+			continue
+		}
+		for _, row := range t.rows {
+			if i >= len(row) || row[i] == nil {
+				continue
+			}
+			if _, numeric := toFloat(row[i]); numeric {
+				t.SetAlign(name, AlignRight)
+			}
 			break
 		}
 	}
-	b.WriteString("\n")
-	b.WriteString(line("+", "-"))
-	b.WriteString("\n")
-	// Rows
+}
+
+// SetSortBy sets the field to sort by and order.
+func (t *Table) SetSortBy(field string, reverse bool) {
+	t.sortBy = field
+	t.reverseSort = reverse
+	t.sortKeys = nil
+}
+
+// SortKey is one column of a multi-column sort
+type SortKey struct {
+	Field   string
+	Reverse bool
+	// Less, if set, compares two cells instead of their string forms or a
+	// comparison registered with SortByCustom
+	Less func(a, b any) bool
+}
+
+// SetSortByMultiple sorts by several columns, the first key being the most
+// significant. The sort is stable, so rows equal on all keys keep their
+// stored order. It replaces any SetSortBy setting.
+func (t *Table) SetSortByMultiple(keys []SortKey) {
+	t.sortKeys = append([]SortKey(nil), keys...)
+	t.sortBy = ""
+	t.reverseSort = false
+	t.InvalidateCache()
+}
+
+// SortStable sorts by field while keeping the current sort order among rows
+// with equal values in field: the new key becomes the most significant and
+// the previous SetSortBy, SetSortByMultiple or SortStable keys break ties.
+// Sorting is always stable, so with no earlier sort equal rows keep their
+// stored order.
+func (t *Table) SortStable(field string, reverse bool) {
+	keys := []SortKey{{Field: field, Reverse: reverse}}
+	prev := t.sortKeys
+	if t.sortBy != "" {
+		prev = []SortKey{{Field: t.sortBy, Reverse: t.reverseSort}}
+	}
+	for _, k := range prev {
+		if k.Field != field {
+			keys = append(keys, k)
+		}
+	}
+	t.SetSortByMultiple(keys)
+}
+
+// SetSortLocale compares cells by the collation rules of a BCP 47 language
+// tag such as "de-DE" or "fr" when sorting, instead of by byte order, so
+// that accented letters sort next to their base letters. Comparisons set
+// with SortByCustom or SortKey.Less are unaffected. An empty locale restores
+// byte order.
+func (t *Table) SetSortLocale(locale string) {
+	t.sortLocale = locale
+}
+
+// SortByCustom sorts by field using less instead of comparing string forms.
+// The comparison is remembered for field, so SetSortBy(field, true) reverses it.
+func (t *Table) SortByCustom(field string, less func(a, b any) bool) {
+	if t.customLess == nil {
+		t.customLess = make(map[string]func(a, b any) bool)
+	}
+	t.customLess[field] = less
+	t.SetSortBy(field, false)
+	t.InvalidateCache()
+}
+
+// SetRowFilter sets a filter function for rows.
+func (t *Table) SetRowFilter(filter func([]any) bool) {
+	t.rowFilter = filter
+	t.InvalidateCache()
+}
+
+// SetNullString sets how nil cells are displayed in text output.
+// Header cells are never substituted.
+func (t *Table) SetNullString(s string) {
+	t.nullString = &s
+}
+
+// GetNullString returns how nil cells are displayed
+func (t *Table) GetNullString() string {
+	if t.nullString != nil {
+		return *t.nullString
+	}
+	return DefaultNullString
+}
+
+// SetBoolStrings sets how true and false cells are displayed in text output,
+// e.g. "Yes" and "No".
+func (t *Table) SetBoolStrings(trueStr, falseStr string) {
+	t.boolStrings = &[2]string{trueStr, falseStr}
+}
+
+// SetColumnBoolStrings sets how true and false cells of one column are
+// displayed, overriding SetBoolStrings.
+func (t *Table) SetColumnBoolStrings(field, trueStr, falseStr string) {
+	if t.columnBoolStrings == nil {
+		t.columnBoolStrings = make(map[string][2]string)
+	}
+	t.columnBoolStrings[field] = [2]string{trueStr, falseStr}
+}
+
+// SetColumnFormat sets a fmt.Sprintf format string, such as "$%.2f" or
+// "%05d", used to display the non-nil cells of a column. A CustomFormat
+// function in the table style takes precedence.
+func (t *Table) SetColumnFormat(field, fmtStr string) {
+	if t.columnFormats == nil {
+		t.columnFormats = make(map[string]string)
+	}
+	t.columnFormats[field] = fmtStr
+}
+
+// ColumnEncoding selects how SetColumnEncoding displays binary data
+type ColumnEncoding int
+
+const (
+	EncNone ColumnEncoding = iota
+	EncBase64
+	EncHex
+	// EncOctal and EncBinary show each byte as a space-separated group of
+	// three octal or eight binary digits
+	EncOctal
+	EncBinary
+)
+
+// SetColumnEncoding displays the []byte and string cells of a column in the
+// given encoding, which is useful for BLOB and BYTEA columns. Other values
+// are formatted as usual. EncNone removes the encoding.
+func (t *Table) SetColumnEncoding(field string, enc ColumnEncoding) {
+	if enc == EncNone {
+		delete(t.encodings, field)
+		return
+	}
+	if t.encodings == nil {
+		t.encodings = make(map[string]ColumnEncoding)
+	}
+	t.encodings[field] = enc
+}
+
+// encode formats data in enc
+func (enc ColumnEncoding) encode(data []byte) string {
+	switch enc {
+	case EncBase64:
+		return base64.StdEncoding.EncodeToString(data)
+	case EncHex:
+		return hex.EncodeToString(data)
+	case EncOctal, EncBinary:
+		verb := "%03o"
+		if enc == EncBinary {
+			verb = "%08b"
+		}
+		groups := make([]string, len(data))
+		for i, c := range data {
+			groups[i] = fmt.Sprintf(verb, c)
+		}
+		return strings.Join(groups, " ")
+	}
+	return string(data)
+}
+
+// SetDateTimeFormat sets the time.Format layout, such as "2006-01-02", used
+// to display time.Time cells of a column. Other values are formatted as usual.
+func (t *Table) SetDateTimeFormat(field, layout string) {
+	if t.timeLayouts == nil {
+		t.timeLayouts = make(map[string]string)
+	}
+	t.timeLayouts[field] = layout
+}
+
+// formatCell returns the display text of a cell in the column at index col
+func (t *Table) formatCell(col int, cell any) string {
+	if cell == nil {
+		return t.GetNullString()
+	}
+	// Rows may be wider than the field names, or the table may have none
+	name := ""
+	if col < len(t.fieldNames) {
+		name = t.fieldNames[col]
+	}
+	if f, ok := t.style.CustomFormat[name]; ok {
+		return f(name, cell)
+	}
+	if enc, ok := t.encodings[name]; ok {
+		switch v := cell.(type) {
+		case []byte:
+			return enc.encode(v)
+		case string:
+			return enc.encode([]byte(v))
+		}
+	}
+	if tm, ok := cell.(time.Time); ok {
+		if layout, ok := t.timeLayouts[name]; ok {
+			return tm.Format(layout)
+		}
+	}
+	if b, ok := cell.(bool); ok {
+		strs := t.boolStrings
+		if s, ok := t.columnBoolStrings[name]; ok {
+			strs = &s
+		}
+		if strs != nil {
+			if b {
+				return strs[0]
+			}
+			return strs[1]
+		}
+	}
+	if f, ok := t.columnFormats[name]; ok {
+		return fmt.Sprintf(f, cell)
+	}
+	return fmt.Sprintf("%v", cell)
+}
+
+// SetCaption sets a caption shown above the table, or below it if below is
+// true, in ASCII, Unicode, HTML and LaTeX output.
+func (t *Table) SetCaption(caption string, below bool) {
+	t.caption = caption
+	t.captionBelow = below
+}
+
+// GetCaption returns the table caption
+func (t *Table) GetCaption() string {
+	return t.caption
+}
+
+// ClearCaption removes the table caption
+func (t *Table) ClearCaption() {
+	t.caption = ""
+	t.captionBelow = false
+}
+
+// SetTableComment sets a comment embedded in HTML output as <!-- comment -->
+// and in LaTeX output as % comment lines, for example to record the
+// generating program.
+func (t *Table) SetTableComment(comment string) {
+	t.comment = comment
+}
+
+// GetTableComment returns the table comment
+func (t *Table) GetTableComment() string {
+	return t.comment
+}
+
+// ClearTableComment removes the table comment
+func (t *Table) ClearTableComment() {
+	t.comment = ""
+}
+
+// writeHTMLComment writes the table comment, if any, as an HTML comment
+func (t *Table) writeHTMLComment(b *errWriter) {
+	if t.comment == "" {
+		return
+	}
+	// "--" may not appear inside an HTML comment
+	b.WriteString("<!-- " + strings.ReplaceAll(t.comment, "--", "- -") + " -->\n")
+}
+
+// writeLaTeXComment writes the table comment, if any, as LaTeX comment lines
+func (t *Table) writeLaTeXComment(b *errWriter) {
+	if t.comment == "" {
+		return
+	}
+	for _, line := range strings.Split(t.comment, "\n") {
+		b.WriteString("% " + line + "\n")
+	}
+}
+
+// SetStyle sets the table style options
+func (t *Table) SetStyle(style TableStyle) {
+	t.style = style
+	t.InvalidateCache()
+}
+
+// SetRowNumberLabel sets the header of the column added by RenderWithRowNumbers.
+// The default is "#".
+func (t *Table) SetRowNumberLabel(label string) {
+	t.rowNumberLabel = label
+}
+
+// RenderWithRowNumbers renders the table as ASCII with an extra leftmost
+// column numbering the rows from startFrom. The table itself is not modified.
+// The label must not be the name of an existing column.
+func (t *Table) RenderWithRowNumbers(startFrom int) string {
+	return renderToString(func(w io.Writer) error { return t.RenderWithRowNumbersToWriter(w, startFrom) })
+}
+
+// RenderWithRowNumbersToWriter writes the output of RenderWithRowNumbers to w
+func (t *Table) RenderWithRowNumbersToWriter(w io.Writer, startFrom int) error {
+	if len(t.fieldNames) == 0 {
+		return t.RenderASCIIToWriter(w)
+	}
+	label := t.rowNumberLabel
+	if label == "" {
+		label = "#"
+	}
+	if slices.Contains(t.fieldNames, label) {
+		return fmt.Errorf("column %q already exists", label)
+	}
+	rows := t.displayRows()
+	numbers := make([]any, len(rows))
+	for i := range rows {
+		numbers[i] = startFrom + i
+	}
+	tmp := t.derive(rows)
+	if err := tmp.InsertColumn(0, label, numbers); err != nil {
+		return err
+	}
+	return tmp.RenderASCIIToWriter(w)
+}
+
+// RenderASCII renders the table as an ASCII string
+func (t *Table) RenderASCII() string {
+	if t.cache == nil {
+		return renderToString(t.RenderASCIIToWriter)
+	}
+	h := t.stateHash()
+	if t.cache.valid && t.cache.hash == h {
+		return t.cache.output
+	}
+	out := renderToString(t.RenderASCIIToWriter)
+	*t.cache = renderCache{valid: true, hash: h, output: out}
+	return out
+}
+
+// EnableCache makes RenderASCII reuse its previous output until the rows or
+// the settings of the table change. Changes made through the table's methods
+// are detected; call InvalidateCache after changes the table cannot see,
+// such as editing a slice after passing it to AddRow, or a filter or
+// formatter starting to return different results because variables it
+// captures changed.
+func (t *Table) EnableCache() {
+	if t.cache == nil {
+		t.cache = &renderCache{}
+	}
+}
+
+// InvalidateCache forces the next RenderASCII call to render the table again.
+// See EnableCache.
+func (t *Table) InvalidateCache() {
+	if t.cache != nil {
+		t.cache.valid = false
+	}
+}
+
+// stateHash hashes the settings that affect rendering, so that it costs
+// nothing per cell. A hash only records the code address of a function, so
+// changes to the rows (through emit) and the setters that store functions
+// invalidate the cache instead.
+func (t *Table) stateHash() uint64 {
+	state := *t
+	state.rows = nil
+	state.watchers = nil
+	state.cache = nil
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%#v", state)
+	if t.nullString != nil {
+		fmt.Fprintf(h, "%q", *t.nullString)
+	}
+	if t.boolStrings != nil {
+		fmt.Fprintf(h, "%q", *t.boolStrings)
+	}
+	return h.Sum64()
+}
+
+// RenderASCIIToWriter writes the ASCII table to w
+func (t *Table) RenderASCIIToWriter(w io.Writer) error {
+	return t.renderGrid(w, gridOptions{box: asciiBox, width: byteWidth, pad: padAlign})
+}
+
+// RenderFixed renders the table as ASCII using the given column widths.
+// Shorter content is padded and longer content is cut, ending with
+// TableStyle.TruncationMarker. Columns missing from widths use their computed
+// width; unknown field names are ignored.
+func (t *Table) RenderFixed(widths map[string]int) string {
+	return renderToString(func(w io.Writer) error { return t.RenderFixedToWriter(w, widths) })
+}
+
+// RenderFixedToWriter writes the output of RenderFixed to w
+func (t *Table) RenderFixedToWriter(w io.Writer, widths map[string]int) error {
+	return t.renderGrid(w, gridOptions{box: asciiBox, width: runeWidth, pad: padAlignUnicode, fixed: widths})
+}
+
+// RenderMySQL renders the table like the MySQL command-line client.
+// The output always uses "-" rules and single-space padding, whatever the
+// table style.
+func (t *Table) RenderMySQL() string {
+	return renderToString(t.RenderMySQLToWriter)
+}
+
+// RenderMySQLToWriter writes the output of RenderMySQL to w
+func (t *Table) RenderMySQLToWriter(w io.Writer) error {
+	return t.renderGrid(w, gridOptions{box: asciiBox, width: runeWidth, pad: padAlignUnicode, defaultStyle: true})
+}
+
+// RenderCompact renders the table as ASCII without padding around cell
+// contents, e.g. "|foo|1|", for narrow displays.
+func (t *Table) RenderCompact() string {
+	return renderToString(t.RenderCompactToWriter)
+}
+
+// RenderCompactToWriter writes the output of RenderCompact to w
+func (t *Table) RenderCompactToWriter(w io.Writer) error {
+	return t.renderGrid(w, gridOptions{box: asciiBox, width: runeWidth, pad: padAlignUnicode, noPadding: true})
+}
+
+// RenderRST renders the table as a reStructuredText grid table
+func (t *Table) RenderRST() string {
+	return renderToString(t.RenderRSTToWriter)
+}
+
+// RenderRSTToWriter writes the output of RenderRST to w
+func (t *Table) RenderRSTToWriter(w io.Writer) error {
+	return t.renderGrid(w, gridOptions{box: asciiBox, width: runeWidth, pad: padAlignUnicode, headerRule: "=", separateAll: true})
+}
+
+// SetEmptyTableMessage sets a message shown inside the table frame when
+// there are no fields or no rows to render, for example because the row
+// filter excludes them all. An empty message restores the default output.
+func (t *Table) SetEmptyTableMessage(msg string) {
+	t.emptyMessage = msg
+}
+
+// SetDataSeparator draws a horizontal rule after every everyN data rows in
+// ASCII and Unicode output. Zero disables the separators.
+func (t *Table) SetDataSeparator(everyN int) {
+	t.dataSeparator = everyN
+}
+
+// GroupSepStyle selects how SetGroupSeparator marks a new group
+type GroupSepStyle int
+
+const (
+	// GroupSepLine draws a horizontal rule
+	GroupSepLine GroupSepStyle = iota
+	// GroupSepLabel draws a rule with the new group's value centered in it:
+	// the first cell of the row that differs from the previous row
+	GroupSepLabel
+	// GroupSepBlank inserts an empty row
+	GroupSepBlank
+)
+
+// SetGroupSeparator marks the start of each group of rows in ASCII and
+// Unicode output. newGroup is called with each pair of adjacent rows in
+// render order and reports whether curr starts a new group. A group
+// separator replaces a SetDataSeparator rule at the same position. A nil
+// newGroup removes the separators.
+func (t *Table) SetGroupSeparator(newGroup func(prev, curr []any) bool, style GroupSepStyle) {
+	t.groupSep = newGroup
+	t.groupSepStyle = style
+	t.InvalidateCache()
+}
+
+// SetHRuleEvery is like SetDataSeparator; zero or negative n disables the rules.
+func (t *Table) SetHRuleEvery(n int) {
+	t.SetDataSeparator(max(n, 0))
+}
+
+// EqualizeColumnWidths makes every column as wide as the widest one in
+// subsequent ASCII and Unicode renders.
+func (t *Table) EqualizeColumnWidths() {
+	widest := 0
+	for _, w := range t.contentWidths(t.displayRows(), runeWidth) {
+		widest = max(widest, w)
+	}
+	t.fixedWidths = make(map[string]int, len(t.fieldNames))
+	for _, name := range t.fieldNames {
+		t.fixedWidths[name] = widest
+	}
+}
+
+// ResetColumnWidths clears all column width overrides.
+func (t *Table) ResetColumnWidths() {
+	t.fixedWidths = nil
+}
+
+// WrapText limits a column to maxWidth characters in bordered renderers by
+// wrapping longer cells onto several lines at spaces. Words longer than
+// maxWidth are broken. A maxWidth of zero or less removes the limit.
+func (t *Table) WrapText(field string, maxWidth int) {
+	if maxWidth <= 0 {
+		delete(t.wrapWidths, field)
+		return
+	}
+	if t.wrapWidths == nil {
+		t.wrapWidths = make(map[string]int)
+	}
+	t.wrapWidths[field] = maxWidth
+}
+
+// SetAutoWrapText limits bordered renderers to maxWidth characters per line,
+// borders and padding included, by wrapping the widest columns. The width
+// left after narrow columns are laid out is shared equally among the columns
+// that do not fit. Columns with a WrapText setting keep it and are not
+// counted in the sharing. A maxWidth of zero or less turns auto-wrap off.
+func (t *Table) SetAutoWrapText(maxWidth int) {
+	t.autoWrapWidth = max(maxWidth, 0)
+}
+
+// autoWrap narrows colWidths to fit the SetAutoWrapText width and reports
+// which columns wrap, including those with a WrapText setting
+func (t *Table) autoWrap(colWidths, padLeft, padRight []int) []bool {
+	wrapped := make([]bool, len(colWidths))
+	avail := t.autoWrapWidth - len(colWidths) - 1
+	var auto []int
+	for i, name := range t.fieldNames {
+		avail -= padLeft[i] + padRight[i]
+		if _, ok := t.wrapWidths[name]; ok {
+			wrapped[i] = true
+			avail -= colWidths[i]
+		} else {
+			auto = append(auto, i)
+		}
+	}
+	if t.autoWrapWidth <= 0 || len(auto) == 0 {
+		return wrapped
+	}
+	// Columns narrower than an equal share keep their width, and the
+	// rest split what remains
+	sort.SliceStable(auto, func(a, b int) bool { return colWidths[auto[a]] < colWidths[auto[b]] })
+	for k, i := range auto {
+		share := avail / (len(auto) - k)
+		if colWidths[i] <= share {
+			avail -= colWidths[i]
+			continue
+		}
+		for n, j := range auto[k:] {
+			w := avail / (len(auto) - k)
+			if n < avail%(len(auto)-k) {
+				w++
+			}
+			colWidths[j] = max(w, 1)
+			wrapped[j] = true
+		}
+		break
+	}
+	return wrapped
+}
+
+// wrapText splits s into lines no wider than w, breaking at spaces and
+// existing newlines where possible
+func wrapText(s string, w int, width func(string) int) []string {
+	if w <= 0 {
+		return []string{s}
+	}
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			for width(word) > w {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				r := []rune(word)
+				n := 0
+				for n < len(r) && width(string(r[:n+1])) <= w {
+					n++
+				}
+				n = max(n, 1)
+				lines = append(lines, string(r[:n]))
+				word = string(r[n:])
+			}
+			switch {
+			case word == "":
+			case line == "":
+				line = word
+			case width(line)+1+width(word) <= w:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// SetColumnPadding sets the number of spaces left and right of the cells of
+// one column in bordered renderers, overriding the PaddingWidth,
+// LeftPaddingWidth and RightPaddingWidth of the style. RenderMySQL and
+// RenderCompact keep their fixed padding.
+func (t *Table) SetColumnPadding(field string, left, right int) {
+	if t.columnPadding == nil {
+		t.columnPadding = make(map[string][2]int)
+	}
+	t.columnPadding[field] = [2]int{left, right}
+}
+
+// SetColumnMaxWidth limits the width of a column in ASCII and Unicode
+// renders; longer content is truncated. Zero or less removes the limit.
+func (t *Table) SetColumnMaxWidth(field string, width int) {
+	if width <= 0 {
+		delete(t.maxWidths, field)
+		return
+	}
+	if t.maxWidths == nil {
+		t.maxWidths = make(map[string]int)
+	}
+	t.maxWidths[field] = width
+}
+
+// AutoFitTerminal sets column maximum widths so that the table, including
+// borders and padding, fits the width of the terminal attached to stdout.
+// Columns are shrunk in proportion to their width. Limits set with
+// SetColumnMaxWidth are kept and only narrowed.
+func (t *Table) AutoFitTerminal() error {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return fmt.Errorf("cannot determine terminal width: %w", err)
+	}
+	return t.fitWidth(width)
+}
+
+// fitWidth sets column maximum widths so the rendered grid is at most total wide
+func (t *Table) fitWidth(total int) error {
+	colWidths := t.gridWidths(t.displayRows(), runeWidth, nil)
+	padLeft, padRight := t.cellPadding(gridOptions{})
+	// Each column adds its padding and a border, plus the leading border
+	available := total - len(colWidths) - 1
+	sum := 0
+	for i, w := range colWidths {
+		available -= padLeft[i] + padRight[i]
+		sum += w
+	}
+	if available < len(colWidths) {
+		return fmt.Errorf("width %d is too narrow for %d columns", total, len(colWidths))
+	}
+	if sum <= available {
+		return nil
+	}
+	for i, name := range t.fieldNames {
+		w := max(1, colWidths[i]*available/sum)
+		if existing, ok := t.maxWidths[name]; ok {
+			w = min(existing, w)
+		}
+		t.SetColumnMaxWidth(name, w)
+	}
+	return nil
+}
+
+// boxChars holds the characters used to draw a grid table
+type boxChars struct {
+	horizontal, vertical               string
+	topLeft, topMid, topRight          string
+	midLeft, midMid, midRight          string
+	bottomLeft, bottomMid, bottomRight string
+}
+
+var (
+	asciiBox   = boxChars{"-", "|", "+", "+", "+", "+", "+", "+", "+", "+", "+"}
+	unicodeBox = boxChars{"─", "│", "┌", "┬", "┐", "├", "┼", "┤", "└", "┴", "┘"}
+	roundedBox = boxChars{"─", "│", "╭", "┬", "╮", "├", "┼", "┤", "╰", "┴", "╯"}
+	doubleBox  = boxChars{"═", "║", "╔", "╦", "╗", "╠", "╬", "╣", "╚", "╩", "╝"}
+	heavyBox   = boxChars{"━", "┃", "┏", "┳", "┓", "┣", "╋", "┫", "┗", "┻", "┛"}
+	// heavyHeaderBox frames a heavy header above light data rows
+	heavyHeaderBox = boxChars{"━", "┃", "┏", "┳", "┓", "┡", "╇", "┩", "┗", "┻", "┛"}
+)
+
+// gridOptions controls how renderGrid draws a table
+type gridOptions struct {
+	box boxChars
+	// header, if set, draws the top line, the header row and the rule under
+	// it instead of box
+	header *boxChars
+	// width measures cell contents and pad aligns them within their column
+	width func(string) int
+	pad   func(string, int, Alignment) string
+	// fixed overrides the computed width of the named columns
+	fixed map[string]int
+	// defaultStyle ignores the table style in favor of the defaults
+	defaultStyle bool
+	// noPadding drops the spaces around cell contents
+	noPadding bool
+	// headerRule, if set, replaces box.horizontal in the rule under the header
+	headerRule string
+	// separateAll draws a rule after every data row
+	separateAll bool
+	// ansi applies the conditional row style as ANSI escape codes
+	ansi bool
+}
+
+// ColWidths returns the width of each column's content as RenderASCII lays
+// it out, in the order of FieldNames. Padding and borders are not included.
+func (t *Table) ColWidths() []int {
+	colWidths := t.gridWidths(t.displayRows(), byteWidth, nil)
+	padLeft, padRight := t.cellPadding(gridOptions{})
+	t.autoWrap(colWidths, padLeft, padRight)
+	return colWidths
+}
+
+// gridWidths computes the column widths of a bordered table from its
+// content and the width settings of the table, with fixed taking precedence
+func (t *Table) gridWidths(rows [][]any, width func(string) int, fixed map[string]int) []int {
+	colWidths := t.dataWidths(rows, width)
+	for i := range t.fieldNames {
+		for _, l := range t.headerText(i) {
+			colWidths[i] = max(colWidths[i], width(l))
+		}
+	}
+	for i, name := range t.fieldNames {
+		if w, ok := t.fixedWidths[name]; ok {
+			colWidths[i] = w
+		}
+		if w, ok := t.maxWidths[name]; ok {
+			colWidths[i] = min(colWidths[i], w)
+		}
+		if w, ok := fixed[name]; ok {
+			colWidths[i] = max(w, 0)
+		}
+		if w, ok := t.wrapWidths[name]; ok {
+			colWidths[i] = min(colWidths[i], w)
+		}
+	}
+	return colWidths
+}
+
+// ansiCodes returns the escape sequence starting each cell of the data row
+// at display index r: a matching cell condition, else the row highlight,
+// else the conditional row style. Highlights are only used if highlight is set.
+func (t *Table) ansiCodes(r int, row []any, highlight bool) []string {
+	rowCode := ""
+	if hl, ok := t.highlights[r]; ok && highlight {
+		rowCode = hl.ansiCodes()
+	} else if t.rowStyle != nil {
+		rowCode = t.rowStyle(r, row).ansiCodes()
+	}
+	codes := make([]string, len(row))
+	for i, cell := range row {
+		codes[i] = rowCode
+		if i >= len(t.fieldNames) || !highlight {
+			continue
+		}
+		for _, h := range t.cellHighlights[t.fieldNames[i]] {
+			if h.cond(cell) {
+				codes[i] = h.style.ansiCodes()
+				break
+			}
+		}
+	}
+	return codes
+}
+
+// cellPadding returns the spaces left and right of each column's cells:
+// the style's widths, then per-column overrides
+func (t *Table) cellPadding(g gridOptions) (padLeft, padRight []int) {
+	style := t.style
+	if g.defaultStyle {
+		style = TableStyle{}
+	}
+	left, right := 1, 1
+	if style.PaddingWidth > 0 {
+		left, right = style.PaddingWidth, style.PaddingWidth
+	}
+	if style.LeftPaddingWidth > 0 {
+		left = style.LeftPaddingWidth
+	}
+	if style.RightPaddingWidth > 0 {
+		right = style.RightPaddingWidth
+	}
+	padLeft = make([]int, len(t.fieldNames))
+	padRight = make([]int, len(t.fieldNames))
+	for i, name := range t.fieldNames {
+		padLeft[i], padRight[i] = left, right
+		if p, ok := t.columnPadding[name]; ok && !g.defaultStyle {
+			padLeft[i], padRight[i] = max(p[0], 0), max(p[1], 0)
+		}
+		if g.noPadding {
+			padLeft[i], padRight[i] = 0, 0
+		}
+	}
+	return padLeft, padRight
+}
+
+// renderGrid writes the table to w as a bordered grid
+func (t *Table) renderGrid(w io.Writer, g gridOptions) error {
+	b := &errWriter{w: w}
+	box, width, pad := g.box, g.width, g.pad
+	if len(t.fieldNames) == 0 {
+		if t.emptyMessage == "" {
+			b.WriteString("(no fields)")
+			return b.err
+		}
+		rule := strings.Repeat(box.horizontal, width(t.emptyMessage)+2)
+		b.WriteString(box.topLeft + rule + box.topRight + "\n")
+		b.WriteString(box.vertical + " " + t.emptyMessage + " " + box.vertical + "\n")
+		b.WriteString(box.bottomLeft + rule + box.bottomRight)
+		return b.err
+	}
+	style := t.style
+	if g.defaultStyle {
+		style = TableStyle{}
+	}
+	padLeft, padRight := t.cellPadding(g)
+	rows := t.displayRows()
+	colWidths := t.gridWidths(rows, width, g.fixed)
+	wrapCols := t.autoWrap(colWidths, padLeft, padRight)
+	// Inner width of the frame, widened if needed to fit the empty message
+	inner := len(colWidths) - 1
+	for i, w := range colWidths {
+		inner += padLeft[i] + w + padRight[i]
+	}
+	showEmpty := len(rows) == 0 && t.emptyMessage != ""
+	if need := width(t.emptyMessage) + 2; showEmpty && need > inner {
+		colWidths[len(colWidths)-1] += need - inner
+		inner = need
+	}
+	// Helper to build a line
+	line := func(left, sep, right, horizontal string) string {
+		var b strings.Builder
+		b.WriteString(left)
+		for i, w := range colWidths {
+			b.WriteString(strings.Repeat(horizontal, padLeft[i]+w+padRight[i]))
+			if i < len(colWidths)-1 {
+				b.WriteString(sep)
+			}
+		}
+		b.WriteString(right)
+		return b.String()
+	}
+	headerBox := box
+	if g.header != nil {
+		headerBox = *g.header
+	}
+	// Helper to build a row of cells, which spans several lines if a
+	// wrapped column needs them
+	cells := func(values []string, vertical string, ansi []string, header bool) string {
+		lines := make([][]string, len(values))
+		height := 1
+		for i, v := range values {
+			lines[i] = []string{v}
+			if header {
+				lines[i] = t.headerText(i)
+			}
+			if wrapCols[i] {
+				var wrapped []string
+				for _, l := range lines[i] {
+					wrapped = append(wrapped, wrapText(l, colWidths[i], width)...)
+				}
+				lines[i] = wrapped
+			}
+			height = max(height, len(lines[i]))
+		}
+		// Header cells are aligned to the bottom of the header block
+		if header {
+			for i := range lines {
+				lines[i] = append(make([]string, height-len(lines[i])), lines[i]...)
+			}
+		}
+		var b strings.Builder
+		for l := range height {
+			if l > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(vertical)
+			for i := range values {
+				align := AlignLeft
+				if a, ok := t.alignments[t.fieldNames[i]]; ok {
+					align = a
+				}
+				if a, ok := t.headerAlignments[t.fieldNames[i]]; ok && header {
+					align = a
+				}
+				v := ""
+				if l < len(lines[i]) {
+					v = lines[i][l]
+				}
+				v = truncate(v, colWidths[i], style.TruncationMarker, width)
+				code := ""
+				if i < len(ansi) {
+					code = ansi[i]
+				}
+				b.WriteString(code)
+				b.WriteString(strings.Repeat(" ", padLeft[i]))
+				b.WriteString(pad(v, colWidths[i], align))
+				b.WriteString(strings.Repeat(" ", padRight[i]))
+				if code != "" {
+					b.WriteString(ansiReset)
+				}
+				b.WriteString(vertical)
+			}
+		}
+		return b.String()
+	}
+	mid := line(box.midLeft, box.midMid, box.midRight, box.horizontal)
+	headerRule := headerBox.horizontal
+	if g.headerRule != "" {
+		headerRule = g.headerRule
+	}
+	headerMid := line(headerBox.midLeft, headerBox.midMid, headerBox.midRight, headerRule)
+	every := t.dataSeparator
+	if g.separateAll {
+		every = 1
+	}
+	// Build table
+	if t.caption != "" && !t.captionBelow {
+		b.WriteString(t.caption)
+		b.WriteString("\n")
+	}
+	b.WriteString(line(headerBox.topLeft, headerBox.topMid, headerBox.topRight, headerBox.horizontal))
+	b.WriteString("\n")
+	// Header
+	b.WriteString(cells(t.fieldNames, headerBox.vertical, nil, true))
+	b.WriteString("\n")
+	b.WriteString(headerMid)
+	b.WriteString("\n")
+	// Rows
+	if showEmpty {
+		b.WriteString(box.vertical + " " + pad(t.emptyMessage, inner-2, AlignLeft) + " " + box.vertical + "\n")
+	}
+	for r, row := range rows {
+		values := make([]string, len(row))
+		for i, cell := range row {
+			values[i] = t.formatCell(i, cell)
+		}
+		switch {
+		case r > 0 && t.groupSep != nil && t.groupSep(rows[r-1], row):
+			switch t.groupSepStyle {
+			case GroupSepBlank:
+				b.WriteString(cells(make([]string, len(values)), box.vertical, nil, false))
+			case GroupSepLabel:
+				b.WriteString(labelRule(mid, t.groupLabel(rows[r-1], row), width))
+			default:
+				b.WriteString(mid)
+			}
+			b.WriteString("\n")
+		case r > 0 && every > 0 && r%every == 0:
+			b.WriteString(mid)
+			b.WriteString("\n")
+		}
+		var ansi []string
+		if g.ansi {
+			ansi = t.ansiCodes(r, row, style.ANSIEnabled)
+		}
+		b.WriteString(cells(values, box.vertical, ansi, false))
+		b.WriteString("\n")
+	}
+	b.WriteString(line(box.bottomLeft, box.bottomMid, box.bottomRight, box.horizontal))
+	if t.caption != "" && t.captionBelow {
+		b.WriteString("\n")
+		b.WriteString(t.caption)
+	}
+	return b.err
+}
+
+// groupLabel returns the first cell of curr that differs from prev
+func (t *Table) groupLabel(prev, curr []any) string {
+	for i, cell := range curr {
+		v := t.formatCell(i, cell)
+		if i >= len(prev) || t.formatCell(i, prev[i]) != v {
+			return v
+		}
+	}
+	return ""
+}
+
+// labelRule centers label, padded with a space on each side, in a rule of
+// single-width characters, leaving the rule as it is if the label does not
+// fit between its ends
+func labelRule(rule, label string, width func(string) int) string {
+	runes := []rune(rule)
+	label = " " + label + " "
+	w := width(label)
+	if label == "  " || w > len(runes)-2 {
+		return rule
+	}
+	start := (len(runes) - w) / 2
+	return string(runes[:start]) + label + string(runes[start+w:])
+}
+
+// stringLess returns the default sort comparison: the string forms of two
+// cells in byte order, or in the collation order of the sort locale
+func (t *Table) stringLess() func(a, b any) bool {
+	if t.sortLocale != "" {
+		c := collate.New(language.Make(t.sortLocale))
+		return func(a, b any) bool {
+			return c.CompareString(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b)) < 0
+		}
+	}
+	return func(a, b any) bool {
+		return fmt.Sprintf("%v", a) < fmt.Sprintf("%v", b)
+	}
+}
+
+// displayRows returns the rows in render order, with the row filter and
+// sorting applied. The stored rows are left untouched.
+func (t *Table) displayRows() [][]any {
+	rows := t.rows
+	// Filtering
+	if t.rowFilter != nil {
+		var filtered [][]any
+		for _, row := range rows {
+			if t.rowFilter(row) {
+				filtered = append(filtered, row)
+			}
+		}
+		rows = filtered
+	}
+	// Sorting
+	keys := t.sortKeys
+	if t.sortBy != "" {
+		keys = []SortKey{{Field: t.sortBy, Reverse: t.reverseSort}}
+	}
+	if len(keys) == 0 {
+		return rows
+	}
+	sorted := make([][]any, len(rows))
+	copy(sorted, rows)
+	less := t.stringLess()
+	// Stable sorts from the least significant key up give a multi-key order
+	for k := len(keys) - 1; k >= 0; k-- {
+		key := keys[k]
+		idx := t.fieldIndex(key.Field)
+		if idx == -1 {
+			continue
+		}
+		cmp := less
+		if custom, ok := t.customLess[key.Field]; ok {
+			cmp = custom
+		}
+		if key.Less != nil {
+			cmp = key.Less
+		}
+		cell := func(i int) any {
+			if idx < len(sorted[i]) {
+				return sorted[i][idx]
+			}
+			return nil
+		}
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if key.Reverse {
+				return cmp(cell(j), cell(i))
+			}
+			return cmp(cell(i), cell(j))
+		})
+	}
+	return sorted
+}
+
+// contentWidths returns the width of the widest header or cell in each column
+func (t *Table) contentWidths(rows [][]any, width func(string) int) []int {
+	colWidths := t.dataWidths(rows, width)
+	for i, name := range t.fieldNames {
+		colWidths[i] = max(colWidths[i], width(name))
+	}
+	return colWidths
+}
+
+// dataWidths returns the width of the widest formatted cell of each column
+func (t *Table) dataWidths(rows [][]any, width func(string) int) []int {
+	colWidths := make([]int, len(t.fieldNames))
 	for _, row := range rows {
-		b.WriteString("|")
 		for i, cell := range row {
-			cellStr := fmt.Sprintf("%v", cell)
-			align := AlignLeft
-			if t.alignments != nil {
-				if a, ok := t.alignments[t.fieldNames[i]]; ok {
-					align = a
-				}
-			}
-			b.WriteString(" ")
-			b.WriteString(padAlign(cellStr, colWidths[i], align))
-			b.WriteString(" |")
-			if i == len(row)-1 {
+			if i >= len(colWidths) {
 				break
 			}
+			if w := width(t.formatCell(i, cell)); w > colWidths[i] {
+				colWidths[i] = w
+			}
 		}
-		b.WriteString("\n")
 	}
-	b.WriteString(line("+", "-"))
-	return b.String()
+	return colWidths
+}
+
+// byteWidth returns the length of s in bytes
+func byteWidth(s string) int {
+	return len(s)
+}
+
+// truncate shortens s to at most w as measured by width, ending with marker
+func truncate(s string, w int, marker string, width func(string) int) string {
+	if width(s) <= w {
+		return s
+	}
+	if width(marker) > w {
+		marker = ""
+	}
+	r := []rune(s)
+	for len(r) > 0 && width(string(r)+marker) > w {
+		r = r[:len(r)-1]
+	}
+	return string(r) + marker
 }
 
 // padString pads s with spaces to width w (left aligned)
@@ -344,6 +2971,33 @@ func padAlign(s string, w int, align Alignment) string {
 
 // FromCSV reads CSV data from an io.Reader and returns a new Table.
 func FromCSV(r io.Reader, delim rune) (*Table, error) {
+	return FromCSVWithOptions(r, CSVOptions{Delimiter: delim})
+}
+
+// CSVOptions controls how FromCSVWithOptions reads CSV data. The fields
+// other than NoHeader map to those of csv.Reader.
+type CSVOptions struct {
+	// Delimiter separates fields; 0 detects it from the start of the data
+	Delimiter rune
+	// LazyQuotes allows quotes in unquoted fields and unescaped quotes in
+	// quoted fields
+	LazyQuotes bool
+	// TrimLeadingSpace ignores white space at the start of fields
+	TrimLeadingSpace bool
+	// Comment, if set, skips lines starting with it
+	Comment rune
+	// FieldsPerRecord is the number of fields each record must have. Zero
+	// requires as many as the first record has; a negative value allows any
+	// number, and short rows are padded with nil.
+	FieldsPerRecord int
+	// NoHeader treats the first record as data, naming the columns
+	// "Field 1", "Field 2" and so on
+	NoHeader bool
+}
+
+// FromCSVWithOptions reads CSV data from an io.Reader and returns a new Table.
+func FromCSVWithOptions(r io.Reader, opts CSVOptions) (*Table, error) {
+	delim := opts.Delimiter
 	if delim == 0 {
 		// Autodetect delimiter from the first line
 		buf := make([]byte, 4096)
@@ -369,98 +3023,823 @@ func FromCSV(r io.Reader, delim rune) (*Table, error) {
 	}
 	reader := csv.NewReader(r)
 	reader.Comma = delim
+	reader.LazyQuotes = opts.LazyQuotes
+	reader.TrimLeadingSpace = opts.TrimLeadingSpace
+	reader.Comment = opts.Comment
+	reader.FieldsPerRecord = opts.FieldsPerRecord
 	records, err := reader.ReadAll()
 	if err != nil {
 		return nil, err
 	}
-	if len(records) == 0 {
-		return nil, fmt.Errorf("CSV is empty")
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV is empty")
+	}
+	headers, rows := records[0], records[1:]
+	if opts.NoHeader {
+		width := 0
+		for _, rec := range records {
+			width = max(width, len(rec))
+		}
+		headers = make([]string, width)
+		for i := range headers {
+			headers[i] = fmt.Sprintf("Field %d", i+1)
+		}
+		rows = records
+	}
+	if opts.FieldsPerRecord >= 0 {
+		return FromRows(headers, rows)
+	}
+	table := NewTableWithFields(headers)
+	for i, rec := range rows {
+		if len(rec) > len(headers) {
+			return nil, fmt.Errorf("row %d has %d columns, expected %d", i, len(rec), len(headers))
+		}
+		row := make([]any, len(headers))
+		for j, v := range rec {
+			row[j] = v
+		}
+		table.rows = append(table.rows, row)
+	}
+	return table, nil
+}
+
+// FromRows returns a new table with the given headers and string rows.
+// Every row must have as many cells as there are headers.
+func FromRows(headers []string, rows [][]string) (*Table, error) {
+	table := NewTableWithFields(headers)
+	for i, row := range rows {
+		if len(row) != len(headers) {
+			return nil, fmt.Errorf("row %d has %d columns, expected %d", i, len(row), len(headers))
+		}
+		rowAny := make([]any, len(row))
+		for j, v := range row {
+			rowAny[j] = v
+		}
+		table.rows = append(table.rows, rowAny)
+	}
+	return table, nil
+}
+
+// FromFile loads a table from a file, choosing the format by extension:
+// .csv with FromCSV, .tsv with FromTSV, .json with FromJSON and .xml with
+// FromXML. Other extensions are read as CSV with the delimiter detected.
+func FromFile(filename string) (*Table, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FromCSV(f, ',')
+	case ".tsv":
+		return FromTSV(f)
+	case ".json":
+		return FromJSON(f)
+	case ".xml":
+		return FromXML(f)
+	}
+	return FromCSV(f, 0)
+}
+
+// FromTSV reads tab-separated values from an io.Reader and returns a new Table.
+func FromTSV(r io.Reader) (*Table, error) {
+	return FromCSV(r, '\t')
+}
+
+// FromJSON reads an array of objects, with columns in first-seen key order,
+// or the {"fields":[...],"rows":[[...]]} form of RenderJSONWithOptions and
+// returns a new Table.
+func FromJSON(r io.Reader) (*Table, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '{' {
+		var doc struct {
+			Fields []string `json:"fields"`
+			Rows   [][]any  `json:"rows"`
+		}
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+		table := NewTableWithFields(doc.Fields)
+		if err := table.SetRows(doc.Rows); err != nil {
+			return nil, err
+		}
+		return table, nil
+	}
+	// Decode token by token to keep the key order of the objects
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, fmt.Errorf("expected a JSON array or object")
+	}
+	var fields []string
+	var objects []map[string]any
+	for dec.More() {
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+			return nil, fmt.Errorf("expected a JSON object in array")
+		}
+		obj := make(map[string]any)
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := tok.(string)
+			var v any
+			if err := dec.Decode(&v); err != nil {
+				return nil, err
+			}
+			if !slices.Contains(fields, key) {
+				fields = append(fields, key)
+			}
+			obj[key] = v
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+	table := NewTableWithFields(fields)
+	for _, obj := range objects {
+		if err := table.AddRowMapPartial(obj); err != nil {
+			return nil, err
+		}
+	}
+	return table, nil
+}
+
+// FromXML reads records from an io.Reader and returns a new Table. Each child
+// of the root element is a row and each of its child elements a cell, named
+// by the element and holding its text:
+//
+//	<people>
+//	  <person><Name>Alice</Name><City>Paris</City></person>
+//	</people>
+//
+// Columns are in first-seen order and cells missing from a row are nil.
+func FromXML(r io.Reader) (*Table, error) {
+	dec := xml.NewDecoder(r)
+	var fields []string
+	var records []map[string]any
+	var record map[string]any
+	var text strings.Builder
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			depth++
+			switch depth {
+			case 2:
+				record = make(map[string]any)
+			case 3:
+				text.Reset()
+			}
+		case xml.CharData:
+			if depth == 3 {
+				text.Write(tok)
+			}
+		case xml.EndElement:
+			switch depth {
+			case 2:
+				records = append(records, record)
+			case 3:
+				name := tok.Name.Local
+				if !slices.Contains(fields, name) {
+					fields = append(fields, name)
+				}
+				record[name] = text.String()
+			}
+			depth--
+		}
+	}
+	if depth != 0 || fields == nil && records == nil {
+		return nil, fmt.Errorf("XML has no records")
+	}
+	table := NewTableWithFields(fields)
+	for _, rec := range records {
+		if err := table.AddRowMapPartial(rec); err != nil {
+			return nil, err
+		}
+	}
+	return table, nil
+}
+
+// ToFile writes t to a file in the given format, which is any format of
+// WriteFormatted or "tsv". An empty format is chosen by extension: .csv,
+// .tsv, .json, .html, .md, .tex or .txt. An unknown format or extension is
+// an error, and no file is created.
+func ToFile(t *Table, filename, format string) (err error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".csv":
+			format = "csv"
+		case ".tsv":
+			format = "tsv"
+		case ".json":
+			format = "json"
+		case ".html", ".htm":
+			format = "html"
+		case ".md":
+			format = "markdown"
+		case ".tex":
+			format = "latex"
+		case ".txt":
+			format = "text"
+		default:
+			return fmt.Errorf("cannot choose a format for %s", filename)
+		}
+	}
+	var write func(io.Writer) error
+	switch strings.ToLower(format) {
+	case "tsv":
+		write = func(w io.Writer) error {
+			return t.ExportCSVWithOptions(w, CSVExportOptions{Delimiter: '\t'})
+		}
+	case "json":
+		// Keep the column order so FromFile restores it
+		write = func(w io.Writer) error {
+			return t.RenderJSONWithOptionsToWriter(w, JSONRenderOptions{Indent: "  "})
+		}
+	default:
+		write = t.formatWriter(format)
+	}
+	if write == nil {
+		return fmt.Errorf("unsupported format %q", format)
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	return write(f)
+}
+
+// FromDBRows creates a Table from a *sql.Rows result set.
+func FromDBRows(rows *sql.Rows) (*Table, error) {
+	return FromDBRowsWithOptions(rows, DBOptions{})
+}
+
+// DBOptions controls how FromDBRowsWithOptions converts scanned values
+type DBOptions struct {
+	// UnwrapSQLNull scans columns whose driver reports sql.NullString,
+	// sql.NullInt64 or another nullable type of database/sql as their scan
+	// type into that type, and stores its value, or nil if it is NULL
+	UnwrapSQLNull bool
+	// NullString, if set, is stored instead of nil for NULL values
+	NullString string
+}
+
+// FromDBRowsWithOptions is like FromDBRows with additional options.
+func FromDBRowsWithOptions(rows *sql.Rows, opts DBOptions) (*Table, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	scanTypes := make([]reflect.Type, len(columns))
+	if opts.UnwrapSQLNull {
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			return nil, err
+		}
+		for i, ct := range types {
+			if st := ct.ScanType(); st != nil && st.PkgPath() == "database/sql" && reflect.PointerTo(st).Implements(scannerType) {
+				scanTypes[i] = st
+			}
+		}
+	}
+	table := NewTableWithFields(columns)
+	for rows.Next() {
+		values := make([]any, len(columns))
+		scanArgs := make([]any, len(columns))
+		for i := range values {
+			if scanTypes[i] != nil {
+				scanArgs[i] = reflect.New(scanTypes[i]).Interface()
+			} else {
+				scanArgs[i] = &values[i]
+			}
+		}
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, err
+		}
+		rowCopy := make([]any, len(values))
+		for i, v := range values {
+			if scanTypes[i] != nil {
+				v = reflect.ValueOf(scanArgs[i]).Elem().Interface()
+			}
+			if opts.UnwrapSQLNull {
+				if v, err = unwrapSQLNull(v); err != nil {
+					return nil, err
+				}
+			}
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+			}
+			if v == nil && opts.NullString != "" {
+				v = opts.NullString
+			}
+			rowCopy[i] = v
+		}
+		table.AddRow(rowCopy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+var scannerType = reflect.TypeFor[sql.Scanner]()
+
+// unwrapSQLNull returns the value held by a nullable type of database/sql,
+// or v itself for other types
+func unwrapSQLNull(v any) (any, error) {
+	valuer, ok := v.(driver.Valuer)
+	if !ok || reflect.TypeOf(v).PkgPath() != "database/sql" {
+		return v, nil
+	}
+	return valuer.Value()
+}
+
+// MapOptions controls how FromSliceOfMapsWithOptions builds a table
+type MapOptions struct {
+	// StrictKeys requires every map to have exactly the same keys
+	StrictKeys bool
+}
+
+// FromSliceOfMaps creates a Table from a slice of maps, such as decoded JSON
+// objects. Columns are the union of all keys in first-seen order; keys new
+// to a map are taken in sorted order since maps are unordered. Missing values are nil.
+func FromSliceOfMaps(data []map[string]any) (*Table, error) {
+	return FromSliceOfMapsWithOptions(data, MapOptions{})
+}
+
+// FromSliceOfMapsWithOptions is like FromSliceOfMaps with additional options.
+func FromSliceOfMapsWithOptions(data []map[string]any, opts MapOptions) (*Table, error) {
+	var fields []string
+	seen := make(map[string]bool)
+	for i, m := range data {
+		if opts.StrictKeys && i > 0 {
+			same := len(m) == len(data[0])
+			for k := range m {
+				if _, ok := data[0][k]; !ok {
+					same = false
+				}
+			}
+			if !same {
+				return nil, fmt.Errorf("map %d has different keys than map 0", i)
+			}
+		}
+		var keys []string
+		for k := range m {
+			if !seen[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			seen[k] = true
+			fields = append(fields, k)
+		}
+	}
+	table := NewTableWithFields(fields)
+	for _, m := range data {
+		if err := table.AddRowMapPartial(m); err != nil {
+			return nil, err
+		}
+	}
+	return table, nil
+}
+
+// FromStructs creates a Table from a slice of structs or struct pointers.
+// Each exported field becomes a column, configured by an optional table tag:
+//
+//	Name  string `table:"Full Name"`  // header text, defaults to the field name
+//	Email string `table:",omitempty"` // nil and zero values become nil cells
+//	ID    int    `table:"ID,order=1"` // columns with an order come first, ascending
+//	Notes string `table:"-"`          // skipped
+func FromStructs(data any) (*Table, error) {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected a slice of structs, got %T", data)
+	}
+	typ := v.Type().Elem()
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a slice of structs, got %T", data)
+	}
+	type column struct {
+		index     int
+		name      string
+		omitEmpty bool
+		order     int
+		hasOrder  bool
+	}
+	var cols []column
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("table")
+		if tag == "-" {
+			continue
+		}
+		col := column{index: i, name: f.Name}
+		parts := strings.Split(tag, ",")
+		if parts[0] != "" {
+			col.name = parts[0]
+		}
+		for _, opt := range parts[1:] {
+			switch {
+			case opt == "omitempty":
+				col.omitEmpty = true
+			case strings.HasPrefix(opt, "order="):
+				n, err := strconv.Atoi(strings.TrimPrefix(opt, "order="))
+				if err != nil {
+					return nil, fmt.Errorf("field %s: invalid order %q", f.Name, opt)
+				}
+				col.order, col.hasOrder = n, true
+			}
+		}
+		cols = append(cols, col)
+	}
+	sort.SliceStable(cols, func(i, j int) bool {
+		if cols[i].hasOrder != cols[j].hasOrder {
+			return cols[i].hasOrder
+		}
+		return cols[i].order < cols[j].order
+	})
+	fields := make([]string, len(cols))
+	for i, col := range cols {
+		fields[i] = col.name
+	}
+	table := NewTableWithFields(fields)
+	for i := range v.Len() {
+		elem := v.Index(i)
+		if elem.Kind() == reflect.Pointer {
+			if elem.IsNil() {
+				return nil, fmt.Errorf("element %d is nil", i)
+			}
+			elem = elem.Elem()
+		}
+		row := make([]any, len(cols))
+		for j, col := range cols {
+			fv := elem.Field(col.index)
+			if col.omitEmpty && fv.IsZero() {
+				continue
+			}
+			row[j] = fv.Interface()
+		}
+		table.rows = append(table.rows, row)
+	}
+	return table, nil
+}
+
+// RenderText renders the table as plain text (same as ASCII)
+func (t *Table) RenderText() string {
+	return t.RenderASCII()
+}
+
+// RenderTextToWriter writes the plain text table to w
+func (t *Table) RenderTextToWriter(w io.Writer) error {
+	return t.RenderASCIIToWriter(w)
+}
+
+// RenderCSV renders the table as CSV
+func (t *Table) RenderCSV() string {
+	return renderToString(t.RenderCSVToWriter)
+}
+
+// SetCSVDelimiter sets the field delimiter of RenderCSV, for example ';'
+// for locales that use the comma as decimal separator. 0 restores ','.
+func (t *Table) SetCSVDelimiter(delim rune) {
+	t.csvDelimiter = delim
+}
+
+// RenderCSVToWriter writes the table to w as CSV
+func (t *Table) RenderCSVToWriter(w io.Writer) error {
+	return t.ExportCSVWithOptions(w, CSVExportOptions{})
+}
+
+// CSVExportOptions controls ExportCSVWithOptions
+type CSVExportOptions struct {
+	// Delimiter separates fields; the default is the one set with
+	// SetCSVDelimiter, or ','
+	Delimiter rune
+	// Quote encloses fields that need quoting; the default is '"'
+	Quote rune
+	// BOM writes a UTF-8 byte order mark first, which Excel needs to
+	// detect the encoding
+	BOM bool
+}
+
+// ExportCSVWithBOM writes the table to w like RenderCSVToWriter, preceded
+// by a UTF-8 byte order mark for Excel
+func (t *Table) ExportCSVWithBOM(w io.Writer) error {
+	return t.ExportCSVWithOptions(w, CSVExportOptions{BOM: true})
+}
+
+// ExportCSVWithOptions writes the table to w as CSV with the given options
+func (t *Table) ExportCSVWithOptions(w io.Writer, opts CSVExportOptions) error {
+	if opts.Delimiter == 0 {
+		opts.Delimiter = t.csvDelimiter
+	}
+	if opts.Delimiter == 0 {
+		opts.Delimiter = ','
+	}
+	if opts.Quote == 0 {
+		opts.Quote = '"'
+	}
+	if !validCSVRune(opts.Delimiter) {
+		return fmt.Errorf("invalid CSV delimiter %q", opts.Delimiter)
+	}
+	if !validCSVRune(opts.Quote) {
+		return fmt.Errorf("invalid CSV quote %q", opts.Quote)
+	}
+	if opts.Delimiter == opts.Quote {
+		return fmt.Errorf("CSV delimiter and quote are both %q", opts.Delimiter)
+	}
+	if opts.BOM {
+		if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+			return err
+		}
+	}
+	records := [][]string{t.fieldNames}
+	for _, row := range t.rows {
+		rec := make([]string, len(row))
+		for i, v := range row {
+			rec[i] = t.formatCell(i, v)
+		}
+		records = append(records, rec)
+	}
+	if opts.Quote == '"' {
+		cw := csv.NewWriter(w)
+		cw.Comma = opts.Delimiter
+		for _, rec := range records {
+			if err := cw.Write(rec); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+	return writeCSVQuoted(w, records, opts.Delimiter, opts.Quote)
+}
+
+// validCSVRune reports whether r can delimit or quote CSV fields
+func validCSVRune(r rune) bool {
+	return r != '\r' && r != '\n' && r != utf8.RuneError && utf8.ValidRune(r)
+}
+
+// writeCSVQuoted writes records like encoding/csv but with a custom quote
+// character, which is doubled inside quoted fields
+func writeCSVQuoted(w io.Writer, records [][]string, delim, quote rune) error {
+	q := string(quote)
+	b := &errWriter{w: w}
+	for _, rec := range records {
+		for i, field := range rec {
+			if i > 0 {
+				b.WriteString(string(delim))
+			}
+			if field != "" && (strings.ContainsAny(field, string(delim)+q+"\r\n") || field[0] == ' ' || field[0] == '\t') {
+				field = q + strings.ReplaceAll(field, q, q+q) + q
+			}
+			b.WriteString(field)
+		}
+		b.WriteString("\n")
+	}
+	return b.err
+}
+
+// RenderJSON renders the table as JSON array of objects
+func (t *Table) RenderJSON() string {
+	return renderToString(t.RenderJSONToWriter)
+}
+
+// RenderJSONToWriter writes the table to w as a JSON array of objects,
+// encoding one row at a time
+func (t *Table) RenderJSONToWriter(w io.Writer) error {
+	b := &errWriter{w: w}
+	if len(t.rows) == 0 {
+		b.WriteString("[]")
+		return b.err
 	}
-	table := NewTableWithFields(records[0])
-	for _, row := range records[1:] {
-		rowAny := make([]any, len(row))
-		for i, v := range row {
-			rowAny[i] = v
+	b.WriteString("[\n  ")
+	for i, row := range t.rows {
+		data, err := json.MarshalIndent(t.rowObject(row), "  ", "  ")
+		if err != nil {
+			return err
 		}
-		table.AddRow(rowAny)
+		if i > 0 {
+			b.WriteString(",\n  ")
+		}
+		b.WriteString(string(data))
 	}
-	return table, nil
+	b.WriteString("\n]")
+	return b.err
 }
 
-// FromDBRows creates a Table from a *sql.Rows result set.
-func FromDBRows(rows *sql.Rows) (*Table, error) {
-	columns, err := rows.Columns()
+// JSONFormat selects the document shape of RenderJSONWithOptions
+type JSONFormat int
+
+const (
+	// JSONObjects is an array with one object per row
+	JSONObjects JSONFormat = iota
+	// JSONFieldsAndRows is an object {"fields":[...],"rows":[[...],...]}
+	JSONFieldsAndRows
+)
+
+// JSONRenderOptions controls RenderJSONWithOptions
+type JSONRenderOptions struct {
+	// Indent is repeated once per nesting level; empty means compact output
+	Indent string
+	// SortKeys orders object keys alphabetically instead of by column
+	SortKeys bool
+	Format   JSONFormat
+}
+
+// RenderJSONWithOptions renders the table as JSON with configurable
+// indentation, key order and document shape.
+func (t *Table) RenderJSONWithOptions(opts JSONRenderOptions) string {
+	return renderToString(func(w io.Writer) error { return t.RenderJSONWithOptionsToWriter(w, opts) })
+}
+
+// RenderJSONWithOptionsToWriter writes the output of RenderJSONWithOptions to w
+func (t *Table) RenderJSONWithOptionsToWriter(w io.Writer, opts JSONRenderOptions) error {
+	var raw []byte
+	var err error
+	switch opts.Format {
+	case JSONFieldsAndRows:
+		rows := t.rows
+		if rows == nil {
+			rows = [][]any{}
+		}
+		raw, err = json.Marshal(struct {
+			Fields []string `json:"fields"`
+			Rows   [][]any  `json:"rows"`
+		}{append([]string{}, t.fieldNames...), rows})
+	default:
+		raw, err = t.jsonObjects(opts.SortKeys)
+	}
 	if err != nil {
-		return nil, err
+		return err
 	}
-	table := NewTableWithFields(columns)
-	for rows.Next() {
-		values := make([]any, len(columns))
-		scanArgs := make([]any, len(columns))
-		for i := range values {
-			scanArgs[i] = &values[i]
+	if opts.Indent != "" {
+		var out bytes.Buffer
+		if err := json.Indent(&out, raw, "", opts.Indent); err != nil {
+			return err
 		}
-		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, err
+		raw = out.Bytes()
+	}
+	_, err = w.Write(raw)
+	return err
+}
+
+// jsonObjects encodes the rows as a compact JSON array of objects, with keys
+// in column order unless sorted is set
+func (t *Table) jsonObjects(sorted bool) ([]byte, error) {
+	order := make([]int, len(t.fieldNames))
+	for i := range order {
+		order[i] = i
+	}
+	if sorted {
+		sort.SliceStable(order, func(a, b int) bool { return t.fieldNames[order[a]] < t.fieldNames[order[b]] })
+	}
+	var b bytes.Buffer
+	b.WriteString("[")
+	for r, row := range t.rows {
+		if r > 0 {
+			b.WriteString(",")
 		}
-		rowCopy := make([]any, len(values))
-		for i, v := range values {
-			if b, ok := v.([]byte); ok {
-				rowCopy[i] = string(b)
-			} else {
-				rowCopy[i] = v
+		b.WriteString("{")
+		first := true
+		for _, j := range order {
+			if j >= len(row) {
+				continue
+			}
+			key, err := json.Marshal(t.fieldNames[j])
+			if err != nil {
+				return nil, err
 			}
+			value, err := json.Marshal(row[j])
+			if err != nil {
+				return nil, err
+			}
+			if !first {
+				b.WriteString(",")
+			}
+			first = false
+			b.Write(key)
+			b.WriteString(":")
+			b.Write(value)
 		}
-		table.AddRow(rowCopy)
+		b.WriteString("}")
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+	b.WriteString("]")
+	return b.Bytes(), nil
+}
+
+// rowObject maps the field names to the values of row
+func (t *Table) rowObject(row []any) map[string]any {
+	obj := make(map[string]any, len(t.fieldNames))
+	for j, name := range t.fieldNames {
+		if j < len(row) {
+			obj[name] = row[j]
+		}
 	}
-	return table, nil
+	return obj
 }
 
-// RenderText renders the table as plain text (same as ASCII)
-func (t *Table) RenderText() string {
-	return t.RenderASCII()
+// RenderNDJSON renders the table as newline-delimited JSON, one object per row
+func (t *Table) RenderNDJSON() string {
+	return renderToString(t.RenderNDJSONToWriter)
 }
 
-// RenderCSV renders the table as CSV
-func (t *Table) RenderCSV() string {
-	var b strings.Builder
-	w := csv.NewWriter(&b)
-	w.Write(t.fieldNames)
+// RenderNDJSONToWriter writes the table to w as newline-delimited JSON
+func (t *Table) RenderNDJSONToWriter(w io.Writer) error {
+	b := &errWriter{w: w}
 	for _, row := range t.rows {
-		rec := make([]string, len(row))
-		for i, v := range row {
-			rec[i] = fmt.Sprintf("%v", v)
+		data, err := json.Marshal(t.rowObject(row))
+		if err != nil {
+			return err
 		}
-		w.Write(rec)
+		b.WriteString(string(data))
+		b.WriteString("\n")
 	}
-	w.Flush()
-	return b.String()
+	return b.err
 }
 
-// RenderJSON renders the table as JSON array of objects
-func (t *Table) RenderJSON() string {
-	objs := make([]map[string]any, len(t.rows))
-	for i, row := range t.rows {
-		obj := make(map[string]any)
-		for j, name := range t.fieldNames {
-			if j < len(row) {
-				obj[name] = row[j]
+// RenderHTML renders the table as an HTML table
+func (t *Table) RenderHTML() string {
+	return renderToString(t.RenderHTMLToWriter)
+}
+
+// RenderHTMLToWriter writes the table to w as an HTML table
+func (t *Table) RenderHTMLToWriter(w io.Writer) error {
+	return t.renderHTML(w, "", nil)
+}
+
+// RenderHTMLWithCSS renders the table like RenderHTML, preceded by a style
+// element. styles maps selectors such as "table", "th", "td" or
+// "tr:nth-child(even)" to declarations such as "padding: 4px". The table
+// gets a class derived from styles and every selector is scoped to it, so
+// the rules do not affect other tables on the page. Without styles the output
+// is that of RenderHTML.
+func (t *Table) RenderHTMLWithCSS(styles map[string]string) string {
+	return renderToString(func(w io.Writer) error { return t.RenderHTMLWithCSSToWriter(w, styles) })
+}
+
+// RenderHTMLWithCSSToWriter writes the output of RenderHTMLWithCSS to w
+func (t *Table) RenderHTMLWithCSSToWriter(w io.Writer, styles map[string]string) error {
+	if len(styles) == 0 {
+		return t.RenderHTMLToWriter(w)
+	}
+	selectors := slices.Sorted(maps.Keys(styles))
+	h := fnv.New32a()
+	for _, sel := range selectors {
+		fmt.Fprintf(h, "%s{%s}", sel, styles[sel])
+	}
+	class := fmt.Sprintf("prettytable-%08x", h.Sum32())
+	// "</" would end the style element early
+	cssEscape := strings.NewReplacer("</", "<\\/").Replace
+	var rules []string
+	for _, sel := range selectors {
+		parts := strings.Split(sel, ",")
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if rest, ok := strings.CutPrefix(part, "table"); ok && (rest == "" || !isCSSNameChar(rest[0])) {
+				parts[i] = "table." + class + rest
+			} else {
+				parts[i] = "." + class + " " + part
 			}
 		}
-		objs[i] = obj
-	}
-	data, err := json.MarshalIndent(objs, "", "  ")
-	if err != nil {
-		return err.Error()
+		rules = append(rules, cssEscape(strings.Join(parts, ", ")+" { "+styles[sel]+" }"))
 	}
-	return string(data)
+	return t.renderHTML(w, class, rules)
 }
 
-// RenderHTML renders the table as an HTML table
-func (t *Table) RenderHTML() string {
+// isCSSNameChar reports whether c can continue a CSS type selector
+func isCSSNameChar(c byte) bool {
+	return c == '-' || c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// renderHTML writes the table as an HTML table with an optional class,
+// preceded by a style element holding cssRules if there are any
+func (t *Table) renderHTML(w io.Writer, class string, cssRules []string) error {
 	escape := func(s string) string {
 		s = strings.ReplaceAll(s, "&", "&amp;")
 		s = strings.ReplaceAll(s, "<", "&lt;")
@@ -468,19 +3847,29 @@ func (t *Table) RenderHTML() string {
 		s = strings.ReplaceAll(s, "\"", "&quot;")
 		return s
 	}
-	var b strings.Builder
-	b.WriteString("<table border=\"1\">\n<tr>")
+	b := &errWriter{w: w}
+	t.writeHTMLComment(b)
+	if len(cssRules) > 0 {
+		b.WriteString("<style>\n" + strings.Join(cssRules, "\n") + "\n</style>\n")
+	}
+	if class != "" {
+		b.WriteString("<table border=\"1\" class=\"" + escape(class) + "\">\n")
+	} else {
+		b.WriteString("<table border=\"1\">\n")
+	}
+	t.writeHTMLCaption(b)
+	b.WriteString("<tr>")
 	for _, name := range t.fieldNames {
 		b.WriteString("<th>")
 		b.WriteString(escape(name))
 		b.WriteString("</th>")
 	}
 	b.WriteString("</tr>\n")
-	for _, row := range t.rows {
-		b.WriteString("<tr>")
+	for r, row := range t.rows {
+		t.writeHTMLRowStart(b, r, row)
 		for i, cell := range row {
 			b.WriteString("<td>")
-			b.WriteString(escape(fmt.Sprintf("%v", cell)))
+			b.WriteString(escape(t.formatCell(i, cell)))
 			b.WriteString("</td>")
 			if i == len(row)-1 {
 				break
@@ -489,11 +3878,301 @@ func (t *Table) RenderHTML() string {
 		b.WriteString("</tr>\n")
 	}
 	b.WriteString("</table>")
-	return b.String()
+	return b.err
+}
+
+// writeHTMLRowStart opens a data row, adding the HTMLClass of the
+// conditional row style if there is one
+func (t *Table) writeHTMLRowStart(b *errWriter, rowIdx int, row []any) {
+	if t.rowStyle != nil {
+		if class := t.rowStyle(rowIdx, row).HTMLClass; class != "" {
+			b.WriteString("<tr class=\"" + htmlEscape(class) + "\">")
+			return
+		}
+	}
+	b.WriteString("<tr>")
+}
+
+// writeHTMLCaption writes the table caption, if any, as an HTML caption element
+func (t *Table) writeHTMLCaption(b *errWriter) {
+	if t.caption == "" {
+		return
+	}
+	if t.captionBelow {
+		b.WriteString("<caption style=\"caption-side: bottom\">")
+	} else {
+		b.WriteString("<caption>")
+	}
+	b.WriteString(htmlEscape(t.caption))
+	b.WriteString("</caption>\n")
+}
+
+// DataTableOptions controls the markup of RenderHTMLDataTable.
+// The zero value emits all attributes.
+type DataTableOptions struct {
+	// Class is the class of the table element; the default is "datatable"
+	Class string
+	// OmitFieldAttr drops the data-field attribute of header cells
+	OmitFieldAttr bool
+	// OmitValueAttr drops the data-value attribute of data cells
+	OmitValueAttr bool
+}
+
+// RenderHTMLDataTable renders the table as HTML ready for DataTables.js, with
+// thead and tbody sections, the field name of each header cell in data-field
+// and the raw value of each data cell in data-value.
+func (t *Table) RenderHTMLDataTable(opts DataTableOptions) string {
+	return renderToString(func(w io.Writer) error { return t.RenderHTMLDataTableToWriter(w, opts) })
+}
+
+// RenderHTMLDataTableToWriter writes the output of RenderHTMLDataTable to w
+func (t *Table) RenderHTMLDataTableToWriter(w io.Writer, opts DataTableOptions) error {
+	class := opts.Class
+	if class == "" {
+		class = "datatable"
+	}
+	b := &errWriter{w: w}
+	t.writeHTMLComment(b)
+	b.WriteString("<table class=\"" + htmlEscape(class) + "\">\n")
+	t.writeHTMLCaption(b)
+	b.WriteString("<thead>\n<tr>")
+	for _, name := range t.fieldNames {
+		if opts.OmitFieldAttr {
+			b.WriteString("<th>")
+		} else {
+			b.WriteString("<th data-field=\"" + htmlEscape(name) + "\">")
+		}
+		b.WriteString(htmlEscape(name))
+		b.WriteString("</th>")
+	}
+	b.WriteString("</tr>\n</thead>\n<tbody>\n")
+	for r, row := range t.rows {
+		t.writeHTMLRowStart(b, r, row)
+		for i, cell := range row {
+			if opts.OmitValueAttr {
+				b.WriteString("<td>")
+			} else {
+				raw := ""
+				if cell != nil {
+					raw = fmt.Sprintf("%v", cell)
+				}
+				b.WriteString("<td data-value=\"" + htmlEscape(raw) + "\">")
+			}
+			b.WriteString(htmlEscape(t.formatCell(i, cell)))
+			b.WriteString("</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>")
+	return b.err
+}
+
+// sortableScript sorts the body rows of a RenderHTMLSortable table by the
+// clicked column, numerically if both values are numbers, and reverses the
+// order on a repeated click
+const sortableScript = `<script>
+function prettytableSort(th) {
+  var body = th.closest("table").tBodies[0];
+  var col = th.cellIndex;
+  var asc = th.getAttribute("data-sort") !== "asc";
+  Array.prototype.forEach.call(th.parentNode.cells, function (c) {
+    c.removeAttribute("data-sort");
+  });
+  th.setAttribute("data-sort", asc ? "asc" : "desc");
+  var rows = Array.prototype.slice.call(body.rows);
+  rows.sort(function (a, b) {
+    var x = a.cells[col].getAttribute("data-value");
+    var y = b.cells[col].getAttribute("data-value");
+    var cmp = x !== "" && y !== "" && isFinite(x) && isFinite(y) ? x - y : x.localeCompare(y);
+    return asc ? cmp : -cmp;
+  });
+  rows.forEach(function (r) {
+    body.appendChild(r);
+  });
+}
+</script>
+`
+
+// RenderHTMLSortable renders the table as a self-contained HTML table whose
+// rows are sorted in the browser by clicking a header cell; clicking again
+// reverses the order. A short script without dependencies is included
+// before the table.
+func (t *Table) RenderHTMLSortable() string {
+	return renderToString(t.RenderHTMLSortableToWriter)
+}
+
+// RenderHTMLSortableToWriter writes the output of RenderHTMLSortable to w
+func (t *Table) RenderHTMLSortableToWriter(w io.Writer) error {
+	b := &errWriter{w: w}
+	t.writeHTMLComment(b)
+	b.WriteString(sortableScript)
+	b.WriteString("<table border=\"1\">\n")
+	t.writeHTMLCaption(b)
+	b.WriteString("<thead>\n<tr>")
+	for _, name := range t.fieldNames {
+		b.WriteString("<th onclick=\"prettytableSort(this)\" style=\"cursor: pointer\">")
+		b.WriteString(htmlEscape(name))
+		b.WriteString("</th>")
+	}
+	b.WriteString("</tr>\n</thead>\n<tbody>\n")
+	for r, row := range t.rows {
+		t.writeHTMLRowStart(b, r, row)
+		for i, cell := range row {
+			raw := ""
+			if cell != nil {
+				raw = fmt.Sprintf("%v", cell)
+			}
+			b.WriteString("<td data-value=\"" + htmlEscape(raw) + "\">")
+			b.WriteString(htmlEscape(t.formatCell(i, cell)))
+			b.WriteString("</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>")
+	return b.err
+}
+
+// BootstrapOpts selects the Bootstrap 5 table classes of RenderHTMLBootstrap
+type BootstrapOpts struct {
+	Striped  bool
+	Hover    bool
+	Bordered bool
+	// Dark styles the header row with table-dark
+	Dark  bool
+	Small bool
+}
+
+// RenderHTMLBootstrap renders the table as HTML styled by Bootstrap 5 classes
+func (t *Table) RenderHTMLBootstrap(opts BootstrapOpts) string {
+	return renderToString(func(w io.Writer) error { return t.RenderHTMLBootstrapToWriter(w, opts) })
+}
+
+// RenderHTMLBootstrapToWriter writes the output of RenderHTMLBootstrap to w
+func (t *Table) RenderHTMLBootstrapToWriter(w io.Writer, opts BootstrapOpts) error {
+	classes := []string{"table"}
+	for _, c := range []struct {
+		on   bool
+		name string
+	}{
+		{opts.Striped, "table-striped"},
+		{opts.Hover, "table-hover"},
+		{opts.Bordered, "table-bordered"},
+		{opts.Small, "table-sm"},
+	} {
+		if c.on {
+			classes = append(classes, c.name)
+		}
+	}
+	b := &errWriter{w: w}
+	t.writeHTMLComment(b)
+	b.WriteString("<table class=\"" + strings.Join(classes, " ") + "\">\n")
+	t.writeHTMLCaption(b)
+	if opts.Dark {
+		b.WriteString("<thead class=\"table-dark\">\n<tr>")
+	} else {
+		b.WriteString("<thead>\n<tr>")
+	}
+	for _, name := range t.fieldNames {
+		b.WriteString("<th scope=\"col\">")
+		b.WriteString(htmlEscape(name))
+		b.WriteString("</th>")
+	}
+	b.WriteString("</tr>\n</thead>\n<tbody>\n")
+	for r, row := range t.rows {
+		t.writeHTMLRowStart(b, r, row)
+		for i, cell := range row {
+			b.WriteString("<td>")
+			b.WriteString(htmlEscape(t.formatCell(i, cell)))
+			b.WriteString("</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>")
+	return b.err
+}
+
+// EmailTheme holds the colors and font of RenderEmailHTMLWithTheme.
+// Empty fields use the defaults of DefaultEmailTheme.
+type EmailTheme struct {
+	HeaderBackground string
+	FontFamily       string
+	BorderColor      string
+}
+
+// DefaultEmailTheme is the theme used by RenderEmailHTML
+var DefaultEmailTheme = EmailTheme{
+	HeaderBackground: "#f2f2f2",
+	FontFamily:       "Arial, Helvetica, sans-serif",
+	BorderColor:      "#ccc",
+}
+
+// RenderEmailHTML renders the table as self-contained HTML for email, with
+// all styles inlined since email clients strip stylesheets.
+func (t *Table) RenderEmailHTML() string {
+	return t.RenderEmailHTMLWithTheme(DefaultEmailTheme)
+}
+
+// RenderEmailHTMLWithTheme is like RenderEmailHTML with custom colors and font.
+func (t *Table) RenderEmailHTMLWithTheme(theme EmailTheme) string {
+	return renderToString(func(w io.Writer) error { return t.RenderEmailHTMLToWriter(w, theme) })
+}
+
+// RenderEmailHTMLToWriter writes the output of RenderEmailHTMLWithTheme to w
+func (t *Table) RenderEmailHTMLToWriter(w io.Writer, theme EmailTheme) error {
+	if theme.HeaderBackground == "" {
+		theme.HeaderBackground = DefaultEmailTheme.HeaderBackground
+	}
+	if theme.FontFamily == "" {
+		theme.FontFamily = DefaultEmailTheme.FontFamily
+	}
+	if theme.BorderColor == "" {
+		theme.BorderColor = DefaultEmailTheme.BorderColor
+	}
+	cellStyle := func(i int) string {
+		align := "left"
+		if i < len(t.fieldNames) {
+			switch t.alignments[t.fieldNames[i]] {
+			case AlignCenter:
+				align = "center"
+			case AlignRight:
+				align = "right"
+			}
+		}
+		return "border:1px solid " + theme.BorderColor + "; border-collapse:collapse; padding:4px; font-family:" +
+			theme.FontFamily + "; text-align:" + align
+	}
+	b := &errWriter{w: w}
+	t.writeHTMLComment(b)
+	b.WriteString("<table cellspacing=\"0\" style=\"border:1px solid " + htmlEscape(theme.BorderColor) +
+		"; border-collapse:collapse; font-family:" + htmlEscape(theme.FontFamily) + "\">\n")
+	t.writeHTMLCaption(b)
+	b.WriteString("<tr>")
+	for i, name := range t.fieldNames {
+		b.WriteString("<th style=\"" + htmlEscape(cellStyle(i)+"; background-color:"+theme.HeaderBackground) + "\">")
+		b.WriteString(htmlEscape(name))
+		b.WriteString("</th>")
+	}
+	b.WriteString("</tr>\n")
+	for _, row := range t.rows {
+		b.WriteString("<tr>")
+		for i, cell := range row {
+			b.WriteString("<td style=\"" + htmlEscape(cellStyle(i)) + "\">")
+			b.WriteString(htmlEscape(t.formatCell(i, cell)))
+			b.WriteString("</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</table>")
+	return b.err
 }
 
 // RenderLaTeX renders the table as LaTeX tabular
 func (t *Table) RenderLaTeX() string {
+	return renderToString(t.RenderLaTeXToWriter)
+}
+
+// RenderLaTeXToWriter writes the table to w as a LaTeX tabular
+func (t *Table) RenderLaTeXToWriter(w io.Writer) error {
 	escape := func(s string) string {
 		s = strings.ReplaceAll(s, "\\", "\\textbackslash{}")
 		s = strings.ReplaceAll(s, "_", "\\_")
@@ -507,7 +4186,14 @@ func (t *Table) RenderLaTeX() string {
 		s = strings.ReplaceAll(s, "^", "\\textasciicircum{}")
 		return s
 	}
-	var b strings.Builder
+	b := &errWriter{w: w}
+	t.writeLaTeXComment(b)
+	if t.caption != "" {
+		b.WriteString("\\begin{table}\n")
+		if !t.captionBelow {
+			b.WriteString("\\caption{" + escape(t.caption) + "}\n")
+		}
+	}
 	b.WriteString("\\begin{tabular}{|" + strings.Repeat("l|", len(t.fieldNames)) + "}\n\\hline\n")
 	for i, name := range t.fieldNames {
 		b.WriteString(escape(name))
@@ -518,7 +4204,7 @@ func (t *Table) RenderLaTeX() string {
 	b.WriteString(" \\ \\hline\n")
 	for _, row := range t.rows {
 		for i, cell := range row {
-			b.WriteString(escape(fmt.Sprintf("%v", cell)))
+			b.WriteString(escape(t.formatCell(i, cell)))
 			if i < len(row)-1 {
 				b.WriteString(" & ")
 			}
@@ -526,12 +4212,89 @@ func (t *Table) RenderLaTeX() string {
 		b.WriteString(" \\ \\hline\n")
 	}
 	b.WriteString("\\end{tabular}")
-	return b.String()
+	if t.caption != "" {
+		if t.captionBelow {
+			b.WriteString("\n\\caption{" + escape(t.caption) + "}")
+		}
+		b.WriteString("\n\\end{table}")
+	}
+	return b.err
+}
+
+// SetLaTeXLabel sets the \label used by RenderLatexLongtable for cross-references
+func (t *Table) SetLaTeXLabel(label string) {
+	t.latexLabel = label
+}
+
+// RenderLatexLongtable renders the table as a LaTeX longtable, which breaks
+// across pages and repeats the header on each one. The caption and label are
+// placed with the first header, or with the last footer if the caption is
+// set to appear below.
+func (t *Table) RenderLatexLongtable() string {
+	return renderToString(t.RenderLatexLongtableToWriter)
+}
+
+// RenderLatexLongtableToWriter writes the output of RenderLatexLongtable to w
+func (t *Table) RenderLatexLongtableToWriter(w io.Writer) error {
+	b := &errWriter{w: w}
+	spec := "|"
+	for _, name := range t.fieldNames {
+		switch t.alignments[name] {
+		case AlignCenter:
+			spec += "c|"
+		case AlignRight:
+			spec += "r|"
+		default:
+			spec += "l|"
+		}
+	}
+	header := make([]string, len(t.fieldNames))
+	for i, name := range t.fieldNames {
+		header[i] = latexEscape(name)
+	}
+	headerLines := "\\hline\n" + strings.Join(header, " & ") + " \\\\\n\\hline\n"
+	var caption string
+	if t.caption != "" {
+		caption = "\\caption{" + latexEscape(t.caption) + "}"
+	}
+	if t.latexLabel != "" {
+		caption += "\\label{" + t.latexLabel + "}"
+	}
+	if caption != "" {
+		caption += " \\\\\n"
+	}
+	t.writeLaTeXComment(b)
+	b.WriteString("\\begin{longtable}{" + spec + "}\n")
+	if !t.captionBelow {
+		b.WriteString(caption)
+	}
+	b.WriteString(headerLines + "\\endfirsthead\n")
+	b.WriteString(headerLines + "\\endhead\n")
+	b.WriteString(fmt.Sprintf("\\hline\n\\multicolumn{%d}{r}{Continued on next page} \\\\\n\\endfoot\n", len(t.fieldNames)))
+	b.WriteString("\\hline\n")
+	if t.captionBelow {
+		b.WriteString(caption)
+	}
+	b.WriteString("\\endlastfoot\n")
+	for _, row := range t.rows {
+		values := make([]string, len(row))
+		for i, cell := range row {
+			values[i] = latexEscape(t.formatCell(i, cell))
+		}
+		b.WriteString(strings.Join(values, " & ") + " \\\\\n")
+	}
+	b.WriteString("\\end{longtable}")
+	return b.err
 }
 
 // RenderMediaWiki renders the table as MediaWiki markup
 func (t *Table) RenderMediaWiki() string {
-	var b strings.Builder
+	return renderToString(t.RenderMediaWikiToWriter)
+}
+
+// RenderMediaWikiToWriter writes the table to w as MediaWiki markup
+func (t *Table) RenderMediaWikiToWriter(w io.Writer) error {
+	b := &errWriter{w: w}
 	b.WriteString("{| class=\"wikitable\"\n|-")
 	for _, name := range t.fieldNames {
 		b.WriteString("! ")
@@ -541,135 +4304,364 @@ func (t *Table) RenderMediaWiki() string {
 	b.WriteString("\n")
 	for _, row := range t.rows {
 		b.WriteString("|-")
-		for _, cell := range row {
+		for i, cell := range row {
 			b.WriteString("| ")
-			b.WriteString(fmt.Sprintf("%v", cell))
+			b.WriteString(t.formatCell(i, cell))
 			b.WriteString(" ")
 		}
 		b.WriteString("\n")
 	}
 	b.WriteString("|}")
-	return b.String()
+	return b.err
 }
 
-// RenderUnicode renders the table using Unicode box-drawing characters
-func (t *Table) RenderUnicode() string {
+// RenderAsciiDoc renders the table as an AsciiDoc table. The cols attribute
+// carries each column's alignment and its share of the total width.
+func (t *Table) RenderAsciiDoc() string {
+	return renderToString(t.RenderAsciiDocToWriter)
+}
+
+// RenderAsciiDocToWriter writes the output of RenderAsciiDoc to w
+func (t *Table) RenderAsciiDocToWriter(w io.Writer) error {
+	b := &errWriter{w: w}
 	if len(t.fieldNames) == 0 {
-		return "(no fields)"
+		b.WriteString("(no fields)")
+		return b.err
 	}
-	// Compute column widths
-	colWidths := make([]int, len(t.fieldNames))
-	for i, name := range t.fieldNames {
-		colWidths[i] = runeWidth(name)
+	colWidths := t.contentWidths(t.rows, runeWidth)
+	total := 0
+	for _, width := range colWidths {
+		total += width
 	}
-	rows := t.rows
-	// Filtering
-	if t.rowFilter != nil {
-		var filtered [][]any
-		for _, row := range rows {
-			if t.rowFilter(row) {
-				filtered = append(filtered, row)
-			}
+	cols := make([]string, len(colWidths))
+	remaining := 100
+	for i, width := range colWidths {
+		pct := remaining
+		if i < len(colWidths)-1 {
+			pct = max(width*100/max(total, 1), 1)
+			remaining -= pct
 		}
-		rows = filtered
-	}
-	// Sorting
-	if t.sortBy != "" {
-		idx := -1
-		for i, name := range t.fieldNames {
-			if name == t.sortBy {
-				idx = i
-				break
-			}
+		marker := "<"
+		switch t.alignments[t.fieldNames[i]] {
+		case AlignCenter:
+			marker = "^"
+		case AlignRight:
+			marker = ">"
 		}
-		if idx != -1 {
-			sorted := make([][]any, len(rows))
-			copy(sorted, rows)
-			less := func(i, j int) bool {
-				si := fmt.Sprintf("%v", sorted[i][idx])
-				sj := fmt.Sprintf("%v", sorted[j][idx])
-				if t.reverseSort {
-					return sj < si
-				}
-				return si < sj
+		cols[i] = fmt.Sprintf("%s%d", marker, pct)
+	}
+	line := func(values []string) {
+		for i, v := range values {
+			if i > 0 {
+				b.WriteString(" ")
 			}
-			sort.Slice(sorted, less)
-			rows = sorted
+			b.WriteString("|" + strings.ReplaceAll(v, "|", "\\|"))
 		}
+		b.WriteString("\n")
 	}
-	for i, name := range t.fieldNames {
-		w := runeWidth(name)
-		if w > colWidths[i] {
-			colWidths[i] = w
+	b.WriteString(fmt.Sprintf("[cols=\"%s\",options=\"header\"]\n|===\n", strings.Join(cols, ",")))
+	line(t.fieldNames)
+	for _, row := range t.rows {
+		values := make([]string, len(row))
+		for i, cell := range row {
+			values[i] = t.formatCell(i, cell)
 		}
+		line(values)
 	}
-	for _, row := range rows {
-		for i, cell := range row {
-			cellStr := fmt.Sprintf("%v", cell)
-			w := runeWidth(cellStr)
-			if w > colWidths[i] {
-				colWidths[i] = w
+	b.WriteString("|===")
+	return b.err
+}
+
+// RenderMoinMoin renders the table as MoinMoin wiki markup with bold header
+// cells. A "||" inside a cell is written as inline code so it does not end
+// the cell.
+func (t *Table) RenderMoinMoin() string {
+	return renderToString(t.RenderMoinMoinToWriter)
+}
+
+// RenderMoinMoinToWriter writes the output of RenderMoinMoin to w
+func (t *Table) RenderMoinMoinToWriter(w io.Writer) error {
+	b := &errWriter{w: w}
+	line := func(values []string, bold bool) {
+		for _, v := range values {
+			v = strings.ReplaceAll(v, "||", "`||`")
+			if bold {
+				v = "'''" + v + "'''"
 			}
+			b.WriteString("||" + v)
 		}
+		b.WriteString("||\n")
 	}
-	// Helper to build a line
-	line := func(left, mid, right, sep string) string {
-		var b strings.Builder
-		b.WriteString(left)
-		for i, w := range colWidths {
-			b.WriteString(strings.Repeat(mid, w+2))
-			if i < len(colWidths)-1 {
-				b.WriteString(sep)
-			}
+	line(t.fieldNames, true)
+	for _, row := range t.displayRows() {
+		values := make([]string, len(row))
+		for i, cell := range row {
+			values[i] = t.formatCell(i, cell)
 		}
-		b.WriteString(right)
-		return b.String()
+		line(values, false)
 	}
-	// Box-drawing chars
+	return b.err
+}
 
-	top := line("┌", "─", "┐", "┬")
-	mid := line("├", "─", "┤", "┼")
-	bot := line("└", "─", "┘", "┴")
-	var b strings.Builder
-	b.WriteString(top)
-	b.WriteString("\n")
-	// Header
-	b.WriteString("│")
-	for i, name := range t.fieldNames {
-		align := AlignLeft
-		if t.alignments != nil {
-			if a, ok := t.alignments[name]; ok {
-				align = a
-			}
+// RenderRTF renders the table as a Rich Text Format document that word
+// processors can open. Column widths assume ten characters per inch and the
+// header row is bold.
+func (t *Table) RenderRTF() string {
+	return renderToString(t.RenderRTFToWriter)
+}
+
+// RenderRTFToWriter writes the output of RenderRTF to w
+func (t *Table) RenderRTFToWriter(w io.Writer) error {
+	const twipsPerChar = 1440 / 10
+	b := &errWriter{w: w}
+	if len(t.fieldNames) == 0 {
+		b.WriteString("(no fields)")
+		return b.err
+	}
+	var def strings.Builder
+	def.WriteString("\\trowd\\trgaph108")
+	right := 0
+	for _, width := range t.contentWidths(t.rows, runeWidth) {
+		right += (width + 2) * twipsPerChar
+		fmt.Fprintf(&def, "\\cellx%d", right)
+	}
+	def.WriteString("\n")
+	align := func(i int) string {
+		switch t.alignments[t.fieldNames[i]] {
+		case AlignCenter:
+			return "\\qc"
+		case AlignRight:
+			return "\\qr"
 		}
-		b.WriteString(" ")
-		b.WriteString(padAlignUnicode(name, colWidths[i], align))
-		b.WriteString(" │")
+		return "\\ql"
 	}
-	b.WriteString("\n")
-	b.WriteString(mid)
-	b.WriteString("\n")
-	// Rows
-	for _, row := range rows {
-		b.WriteString("│")
+	b.WriteString("{\\rtf1\\ansi\\deff0\n{\\fonttbl{\\f0 Arial;}}\n")
+	b.WriteString(def.String())
+	for i, name := range t.fieldNames {
+		b.WriteString("\\pard\\intbl" + align(i) + "\\b " + rtfEscape(name) + "\\b0\\cell")
+	}
+	b.WriteString("\\row\n")
+	for _, row := range t.rows {
+		b.WriteString(def.String())
 		for i, cell := range row {
-			cellStr := fmt.Sprintf("%v", cell)
-			align := AlignLeft
-			if t.alignments != nil {
-				if a, ok := t.alignments[t.fieldNames[i]]; ok {
-					align = a
-				}
+			b.WriteString("\\pard\\intbl" + align(i) + " " + rtfEscape(t.formatCell(i, cell)) + "\\cell")
+		}
+		b.WriteString("\\row\n")
+	}
+	b.WriteString("}")
+	return b.err
+}
+
+// rtfEscape escapes RTF control characters and encodes non-ASCII runes as
+// \uN? sequences
+func rtfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '{' || r == '}':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString("\\line ")
+		case r > 127:
+			for _, u := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&b, "\\u%d?", int16(u))
 			}
-			b.WriteString(" ")
-			b.WriteString(padAlignUnicode(cellStr, colWidths[i], align))
-			b.WriteString(" │")
+		default:
+			b.WriteRune(r)
 		}
-		b.WriteString("\n")
 	}
-	b.WriteString(bot)
 	return b.String()
 }
 
+// RenderUnicode renders the table using Unicode box-drawing characters
+func (t *Table) RenderUnicode() string {
+	return renderToString(t.RenderUnicodeToWriter)
+}
+
+// RenderUnicodeToWriter writes the Unicode box-drawing table to w
+func (t *Table) RenderUnicodeToWriter(w io.Writer) error {
+	return t.renderGrid(w, gridOptions{box: unicodeBox, width: runeWidth, pad: padAlignUnicode})
+}
+
+// ANSIColor is a terminal color for RenderANSI. The zero value keeps the
+// terminal's default color.
+type ANSIColor int
+
+const (
+	ColorDefault ANSIColor = iota
+	ColorBlack
+	ColorRed
+	ColorGreen
+	ColorYellow
+	ColorBlue
+	ColorMagenta
+	ColorCyan
+	ColorWhite
+)
+
+const ansiReset = "\x1b[0m"
+
+// RowStyle is the style of one data row. FG, BG, Bold and Italic apply to
+// RenderANSI; HTMLClass is added to the row's tr element in HTML output.
+type RowStyle struct {
+	FG, BG    ANSIColor
+	Bold      bool
+	Italic    bool
+	HTMLClass string
+}
+
+// ansiCodes returns the escape sequence that starts the style, or "" if the
+// style sets no ANSI attributes
+func (s RowStyle) ansiCodes() string {
+	return CellStyle{FG: s.FG, BG: s.BG, Bold: s.Bold, Italic: s.Italic}.ansiCodes()
+}
+
+// CellStyle is a set of ANSI text attributes
+type CellStyle struct {
+	FG, BG    ANSIColor
+	Bold      bool
+	Italic    bool
+	Underline bool
+	Reverse   bool
+}
+
+// Common cell styles
+var (
+	StyleBold      = CellStyle{Bold: true}
+	StyleItalic    = CellStyle{Italic: true}
+	StyleUnderline = CellStyle{Underline: true}
+	StyleReverse   = CellStyle{Reverse: true}
+)
+
+// ansiCodes returns the escape sequence that starts the style, or "" if the
+// style sets no attributes
+func (s CellStyle) ansiCodes() string {
+	var codes []string
+	if s.Bold {
+		codes = append(codes, "1")
+	}
+	if s.Italic {
+		codes = append(codes, "3")
+	}
+	if s.Underline {
+		codes = append(codes, "4")
+	}
+	if s.Reverse {
+		codes = append(codes, "7")
+	}
+	if s.FG != ColorDefault {
+		codes = append(codes, strconv.Itoa(29+int(s.FG)))
+	}
+	if s.BG != ColorDefault {
+		codes = append(codes, strconv.Itoa(39+int(s.BG)))
+	}
+	if len(codes) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+// SetConditionalRowStyle sets a function choosing the style of each data
+// row. It is called while rendering with the row's position in the output
+// and its values; pass nil to remove it.
+func (t *Table) SetConditionalRowStyle(fn func(rowIdx int, row []any) RowStyle) {
+	t.rowStyle = fn
+	t.InvalidateCache()
+}
+
+// SetRowHighlight styles the data row at a display index (after filtering
+// and sorting) in RenderANSI, taking precedence over the conditional row style.
+// The highlight is only drawn if ANSIEnabled is set in the table style.
+func (t *Table) SetRowHighlight(index int, style CellStyle) {
+	if t.highlights == nil {
+		t.highlights = make(map[int]CellStyle)
+	}
+	t.highlights[index] = style
+}
+
+// ClearRowHighlight removes the highlight of one row
+func (t *Table) ClearRowHighlight(index int) {
+	delete(t.highlights, index)
+}
+
+// ClearAllHighlights removes all row highlights
+func (t *Table) ClearAllHighlights() {
+	t.highlights = nil
+}
+
+// cellHighlight styles the cells matching cond
+type cellHighlight struct {
+	cond  func(any) bool
+	style CellStyle
+}
+
+// SetHighlightCondition styles the cells of field for which cond returns
+// true in RenderANSI, taking precedence over row styles. Conditions for the
+// same field are checked in the order they were added and the first match wins.
+// The highlights are only drawn if ANSIEnabled is set in the table style.
+func (t *Table) SetHighlightCondition(field string, cond func(any) bool, style CellStyle) {
+	if t.cellHighlights == nil {
+		t.cellHighlights = make(map[string][]cellHighlight)
+	}
+	t.cellHighlights[field] = append(t.cellHighlights[field], cellHighlight{cond, style})
+	t.InvalidateCache()
+}
+
+// RenderANSI renders the table like RenderUnicode, with data rows styled by
+// row highlights, highlight conditions and the conditional row style. Row
+// highlights and highlight conditions are only drawn if ANSIEnabled is set in
+// the table style.
+func (t *Table) RenderANSI() string {
+	return renderToString(t.RenderANSIToWriter)
+}
+
+// RenderANSIToWriter writes the output of RenderANSI to w
+func (t *Table) RenderANSIToWriter(w io.Writer) error {
+	return t.renderGrid(w, gridOptions{box: unicodeBox, width: runeWidth, pad: padAlignUnicode, ansi: true})
+}
+
+// RenderUnicodeRounded is like RenderUnicode but with rounded outer corners
+func (t *Table) RenderUnicodeRounded() string {
+	return renderToString(t.RenderUnicodeRoundedToWriter)
+}
+
+// RenderUnicodeRoundedToWriter writes the output of RenderUnicodeRounded to w
+func (t *Table) RenderUnicodeRoundedToWriter(w io.Writer) error {
+	return t.renderGrid(w, gridOptions{box: roundedBox, width: runeWidth, pad: padAlignUnicode})
+}
+
+// RenderUnicodeDouble renders the table using double-line box-drawing characters
+func (t *Table) RenderUnicodeDouble() string {
+	return renderToString(t.RenderUnicodeDoubleToWriter)
+}
+
+// RenderUnicodeDoubleToWriter writes the output of RenderUnicodeDouble to w
+func (t *Table) RenderUnicodeDoubleToWriter(w io.Writer) error {
+	return t.renderGrid(w, gridOptions{box: doubleBox, width: runeWidth, pad: padAlignUnicode})
+}
+
+// RenderUnicodeHeavy renders the table using heavy box-drawing characters
+func (t *Table) RenderUnicodeHeavy() string {
+	return renderToString(t.RenderUnicodeHeavyToWriter)
+}
+
+// RenderUnicodeHeavyToWriter writes the output of RenderUnicodeHeavy to w
+func (t *Table) RenderUnicodeHeavyToWriter(w io.Writer) error {
+	return t.renderGrid(w, gridOptions{box: heavyBox, width: runeWidth, pad: padAlignUnicode})
+}
+
+// RenderUnicodeHeavyHeader renders the header with heavy box-drawing
+// characters and the data rows with light ones
+func (t *Table) RenderUnicodeHeavyHeader() string {
+	return renderToString(t.RenderUnicodeHeavyHeaderToWriter)
+}
+
+// RenderUnicodeHeavyHeaderToWriter writes the output of RenderUnicodeHeavyHeader to w
+func (t *Table) RenderUnicodeHeavyHeaderToWriter(w io.Writer) error {
+	return t.renderGrid(w, gridOptions{box: unicodeBox, header: &heavyHeaderBox, width: runeWidth, pad: padAlignUnicode})
+}
+
 // runeWidth returns the number of runes (Unicode code points) in a string
 func runeWidth(s string) int {
 	return len([]rune(s))
@@ -708,10 +4700,16 @@ func latexEscape(s string) string {
 
 // RenderMarkdown renders the table as GitHub-flavored Markdown
 func (t *Table) RenderMarkdown() string {
+	return renderToString(t.RenderMarkdownToWriter)
+}
+
+// RenderMarkdownToWriter writes the table to w as GitHub-flavored Markdown
+func (t *Table) RenderMarkdownToWriter(w io.Writer) error {
+	b := &errWriter{w: w}
 	if len(t.fieldNames) == 0 {
-		return "(no fields)"
+		b.WriteString("(no fields)")
+		return b.err
 	}
-	var b strings.Builder
 	// Header row
 	b.WriteString("| ")
 	for i, name := range t.fieldNames {
@@ -729,41 +4727,503 @@ func (t *Table) RenderMarkdown() string {
 			break
 		}
 	}
-	b.WriteString("\n")
 	// Data rows
 	for _, row := range t.rows {
-		b.WriteString("| ")
+		b.WriteString("\n| ")
 		for i, cell := range row {
-			b.WriteString(fmt.Sprintf("%v", cell))
+			b.WriteString(t.formatCell(i, cell))
 			b.WriteString(" | ")
 			if i == len(row)-1 {
 				break
 			}
 		}
+	}
+	return b.err
+}
+
+// RenderMarkdownAligned renders the table as GitHub-flavored Markdown with
+// cells padded so columns line up. Column alignments are encoded in the
+// separator row; columns without an alignment get a plain "---".
+func (t *Table) RenderMarkdownAligned() string {
+	return renderToString(t.RenderMarkdownAlignedToWriter)
+}
+
+// RenderMarkdownAlignedToWriter writes the output of RenderMarkdownAligned to w
+func (t *Table) RenderMarkdownAlignedToWriter(w io.Writer) error {
+	b := &errWriter{w: w}
+	if len(t.fieldNames) == 0 {
+		b.WriteString("(no fields)")
+		return b.err
+	}
+	colWidths := t.contentWidths(t.rows, runeWidth)
+	for i := range colWidths {
+		colWidths[i] = max(colWidths[i], 3)
+	}
+	alignment := func(i int) (Alignment, bool) {
+		a, ok := t.alignments[t.fieldNames[i]]
+		return a, ok
+	}
+	line := func(values []string) {
+		b.WriteString("|")
+		for i, v := range values {
+			a, _ := alignment(i)
+			b.WriteString(" " + padAlignUnicode(v, colWidths[i], a) + " |")
+		}
+	}
+	line(t.fieldNames)
+	seps := make([]string, len(colWidths))
+	for i, width := range colWidths {
+		a, ok := alignment(i)
+		switch {
+		case !ok:
+			seps[i] = strings.Repeat("-", width)
+		case a == AlignCenter:
+			seps[i] = ":" + strings.Repeat("-", width-2) + ":"
+		case a == AlignRight:
+			seps[i] = strings.Repeat("-", width-1) + ":"
+		default:
+			seps[i] = ":" + strings.Repeat("-", width-1)
+		}
+	}
+	b.WriteString("\n")
+	line(seps)
+	for _, row := range t.rows {
+		values := make([]string, len(row))
+		for i, cell := range row {
+			values[i] = t.formatCell(i, cell)
+		}
+		b.WriteString("\n")
+		line(values)
+	}
+	return b.err
+}
+
+// RenderPSQL renders the table like the PostgreSQL client's aligned format:
+// no outer border, centered headers and a dashed rule below them.
+func (t *Table) RenderPSQL() string {
+	return renderToString(t.RenderPSQLToWriter)
+}
+
+// RenderPSQLToWriter writes the output of RenderPSQL to w
+func (t *Table) RenderPSQLToWriter(w io.Writer) error {
+	b := &errWriter{w: w}
+	if len(t.fieldNames) == 0 {
+		b.WriteString("(no fields)")
+		return b.err
+	}
+	rows := t.displayRows()
+	colWidths := t.contentWidths(rows, runeWidth)
+	// Header
+	for i, name := range t.fieldNames {
+		if i > 0 {
+			b.WriteString("|")
+		}
+		b.WriteString(" " + padAlignUnicode(name, colWidths[i], AlignCenter) + " ")
+	}
+	b.WriteString("\n")
+	for i, width := range colWidths {
+		if i > 0 {
+			b.WriteString("+")
+		}
+		b.WriteString(strings.Repeat("-", width+2))
+	}
+	// Rows, without trailing padding like psql
+	for _, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			if i > 0 {
+				line.WriteString("|")
+			}
+			align := AlignLeft
+			if a, ok := t.alignments[t.fieldNames[i]]; ok {
+				align = a
+			}
+			line.WriteString(" " + padAlignUnicode(t.formatCell(i, cell), colWidths[i], align) + " ")
+		}
+		b.WriteString("\n")
+		b.WriteString(strings.TrimRight(line.String(), " "))
+	}
+	if t.style.PSQLRowCount {
+		if len(rows) == 1 {
+			b.WriteString("\n(1 row)")
+		} else {
+			b.WriteString(fmt.Sprintf("\n(%d rows)", len(rows)))
+		}
+	}
+	return b.err
+}
+
+// RenderSimple renders the table without a frame: the header, a dashed rule
+// under each column and the data rows, with columns separated by two spaces.
+func (t *Table) RenderSimple() string {
+	return renderToString(t.RenderSimpleToWriter)
+}
+
+// RenderSimpleToWriter writes the output of RenderSimple to w
+func (t *Table) RenderSimpleToWriter(w io.Writer) error {
+	return t.renderSeparated(w, separatedOptions{sep: "  ", rule: "-"})
+}
+
+// RenderPlainText renders the table as whitespace-aligned columns separated
+// by two spaces, without any rule or border characters
+func (t *Table) RenderPlainText() string {
+	return renderToString(t.RenderPlainTextToWriter)
+}
+
+// RenderPlainTextToWriter writes the output of RenderPlainText to w
+func (t *Table) RenderPlainTextToWriter(w io.Writer) error {
+	return t.renderSeparated(w, separatedOptions{sep: "  "})
+}
+
+// RenderSSV renders the table as fixed-width columns separated by a single
+// space, with no rules or borders, for fixed-width file formats
+func (t *Table) RenderSSV() string {
+	return renderToString(t.RenderSSVToWriter)
+}
+
+// RenderSSVToWriter writes the output of RenderSSV to w
+func (t *Table) RenderSSVToWriter(w io.Writer) error {
+	return t.renderSeparated(w, separatedOptions{sep: " "})
+}
+
+// RenderBorderless renders the header and rows with cells joined by sep and
+// no borders or rules. If padToWidth is set, cells are padded to their
+// column width so the columns line up.
+func (t *Table) RenderBorderless(sep string, padToWidth bool) string {
+	return renderToString(func(w io.Writer) error { return t.RenderBorderlessToWriter(w, sep, padToWidth) })
+}
+
+// RenderBorderlessToWriter writes the output of RenderBorderless to w
+func (t *Table) RenderBorderlessToWriter(w io.Writer, sep string, padToWidth bool) error {
+	return t.renderSeparated(w, separatedOptions{sep: sep, unpadded: !padToWidth})
+}
+
+// RenderRSTSimple renders the table as a reStructuredText simple table
+func (t *Table) RenderRSTSimple() string {
+	return renderToString(t.RenderRSTSimpleToWriter)
+}
+
+// RenderRSTSimpleToWriter writes the output of RenderRSTSimple to w
+func (t *Table) RenderRSTSimpleToWriter(w io.Writer) error {
+	return t.renderSeparated(w, separatedOptions{sep: "  ", rule: "=", frame: true})
+}
+
+// separatedOptions controls how renderSeparated lays out a table
+type separatedOptions struct {
+	// sep joins the cells of a line
+	sep string
+	// rule, if set, is repeated under each header cell
+	rule string
+	// frame also draws the rule above the header and below the rows
+	frame bool
+	// unpadded writes cells as they are instead of padding them to the
+	// column width
+	unpadded bool
+}
+
+// renderSeparated writes the header and rows with cells padded to their
+// column width and joined by a separator, without borders.
+func (t *Table) renderSeparated(w io.Writer, opts separatedOptions) error {
+	b := &errWriter{w: w}
+	if len(t.fieldNames) == 0 {
+		b.WriteString("(no fields)")
+		return b.err
+	}
+	rows := t.displayRows()
+	colWidths := t.contentWidths(rows, runeWidth)
+	line := func(values []string) {
+		for i, v := range values {
+			if i > 0 {
+				b.WriteString(opts.sep)
+			}
+			if opts.unpadded {
+				b.WriteString(v)
+				continue
+			}
+			align := AlignLeft
+			if a, ok := t.alignments[t.fieldNames[i]]; ok {
+				align = a
+			}
+			b.WriteString(padAlignUnicode(v, colWidths[i], align))
+		}
+	}
+	rules := make([]string, len(colWidths))
+	for i, width := range colWidths {
+		rules[i] = strings.Repeat(opts.rule, width)
+	}
+	if opts.frame {
+		line(rules)
+		b.WriteString("\n")
+	}
+	line(t.fieldNames)
+	if opts.rule != "" {
+		b.WriteString("\n")
+		line(rules)
+	}
+	for _, row := range rows {
+		values := make([]string, len(row))
+		for i, cell := range row {
+			values[i] = t.formatCell(i, cell)
+		}
+		b.WriteString("\n")
+		line(values)
+	}
+	if opts.frame {
 		b.WriteString("\n")
+		line(rules)
+	}
+	return b.err
+}
+
+// RenderGo renders the stored rows as a gofmt-formatted Go [][]any literal,
+// with a comment listing the field names above each row.
+func (t *Table) RenderGo() string {
+	return renderToString(t.RenderGoToWriter)
+}
+
+// RenderGoToWriter writes the output of RenderGo to w
+func (t *Table) RenderGoToWriter(w io.Writer) error {
+	b := &errWriter{w: w}
+	comment := "\t// " + strings.Join(t.fieldNames, ", ") + "\n"
+	b.WriteString("[][]any{\n")
+	for _, row := range t.rows {
+		values := make([]string, len(row))
+		for i, cell := range row {
+			values[i] = goLiteral(cell)
+		}
+		if len(t.fieldNames) > 0 {
+			b.WriteString(comment)
+		}
+		b.WriteString("\t{" + strings.Join(values, ", ") + "},\n")
+	}
+	b.WriteString("}")
+	return b.err
+}
+
+// goLiteral returns v as a Go expression that evaluates to the same value
+// when stored in an any
+func goLiteral(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(x)
+	case bool:
+		return strconv.FormatBool(x)
+	case int:
+		return strconv.Itoa(x)
+	case float64:
+		return goFloat(x, 64)
+	case float32:
+		return "float32(" + goFloat(float64(x), 32) + ")"
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%T(%d)", x, x)
 	}
-	return strings.TrimRight(b.String(), "\n")
+	return fmt.Sprintf("%#v", v)
+}
+
+// goFloat formats f as a float literal, adding ".0" to whole numbers
+func goFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return "math.NaN()"
+	case math.IsInf(f, 1):
+		return "math.Inf(1)"
+	case math.IsInf(f, -1):
+		return "math.Inf(-1)"
+	}
+	s := strconv.FormatFloat(f, 'g', -1, bitSize)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+// TemplateData is the value RenderTemplate executes templates against
+type TemplateData struct {
+	FieldNames []string
+	Rows       [][]any
+	Style      TableStyle
+}
+
+// ParseTemplate parses src as a text/template named "table"
+func ParseTemplate(src string) (*template.Template, error) {
+	return template.New("table").Parse(src)
+}
+
+// RenderTemplate executes tmpl with the field names, the rows in render
+// order and the style of the table.
+func (t *Table) RenderTemplate(tmpl *template.Template) (string, error) {
+	var b strings.Builder
+	if err := t.RenderTemplateToWriter(&b, tmpl); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// RenderTemplateToWriter writes the output of RenderTemplate to w
+func (t *Table) RenderTemplateToWriter(w io.Writer, tmpl *template.Template) error {
+	return tmpl.Execute(w, TemplateData{
+		FieldNames: append([]string(nil), t.fieldNames...),
+		Rows:       copyRows(t.displayRows()),
+		Style:      t.style,
+	})
 }
 
 // GetFormattedString returns the table as a string in the specified format.
-// Supported formats: "text", "ascii", "csv", "json", "html", "latex", "mediawiki", "markdown"
+// Supported formats are those of WriteFormatted.
 func (t *Table) GetFormattedString(format string) string {
+	return renderToString(func(w io.Writer) error { return t.WriteFormatted(w, format) })
+}
+
+// WriteFormatted writes the table to w in the specified format.
+// Supported formats: "text", "ascii", "unicode", "csv", "json", "ndjson", "html", "latex", "mediawiki", "markdown",
+// "markdown-aligned", "psql", "mysql", "simple", "compact", "rst", "rst-simple", "asciidoc",
+// "unicode-rounded", "unicode-double", "unicode-heavy", "unicode-heavy-header", "ansi",
+// "plain", "ssv", "moinmoin"
+// Unknown formats fall back to ASCII.
+func (t *Table) WriteFormatted(w io.Writer, format string) error {
+	if write := t.formatWriter(format); write != nil {
+		return write(w)
+	}
+	return t.RenderASCIIToWriter(w)
+}
+
+// formatWriter returns the writer of a WriteFormatted format, or nil if the
+// format is unknown
+func (t *Table) formatWriter(format string) func(io.Writer) error {
 	switch strings.ToLower(format) {
 	case "text", "ascii":
-		return t.RenderASCII()
+		return t.RenderASCIIToWriter
+	case "unicode":
+		return t.RenderUnicodeToWriter
+	case "unicode-rounded":
+		return t.RenderUnicodeRoundedToWriter
+	case "ansi":
+		return t.RenderANSIToWriter
+	case "unicode-double":
+		return t.RenderUnicodeDoubleToWriter
+	case "unicode-heavy":
+		return t.RenderUnicodeHeavyToWriter
+	case "unicode-heavy-header":
+		return t.RenderUnicodeHeavyHeaderToWriter
 	case "csv":
-		return t.RenderCSV()
+		return t.RenderCSVToWriter
 	case "json":
-		return t.RenderJSON()
+		return t.RenderJSONToWriter
+	case "ndjson":
+		return t.RenderNDJSONToWriter
 	case "html":
-		return t.RenderHTML()
+		return t.RenderHTMLToWriter
 	case "latex":
-		return t.RenderLaTeX()
+		return t.RenderLaTeXToWriter
 	case "mediawiki":
-		return t.RenderMediaWiki()
+		return t.RenderMediaWikiToWriter
 	case "markdown":
-		return t.RenderMarkdown()
-	default:
-		return t.RenderASCII()
+		return t.RenderMarkdownToWriter
+	case "markdown-aligned":
+		return t.RenderMarkdownAlignedToWriter
+	case "psql":
+		return t.RenderPSQLToWriter
+	case "mysql":
+		return t.RenderMySQLToWriter
+	case "simple":
+		return t.RenderSimpleToWriter
+	case "compact":
+		return t.RenderCompactToWriter
+	case "plain":
+		return t.RenderPlainTextToWriter
+	case "ssv":
+		return t.RenderSSVToWriter
+	case "moinmoin":
+		return t.RenderMoinMoinToWriter
+	case "asciidoc":
+		return t.RenderAsciiDocToWriter
+	case "rst":
+		return t.RenderRSTToWriter
+	case "rst-simple":
+		return t.RenderRSTSimpleToWriter
+	}
+	return nil
+}
+
+// errWriter wraps an io.Writer and keeps the first write error, so
+// renderers can write unconditionally and check once at the end
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+// WriteString writes s unless an earlier write failed
+func (e *errWriter) WriteString(s string) {
+	if e.err == nil {
+		_, e.err = io.WriteString(e.w, s)
+	}
+}
+
+// renderToString runs a writer-based renderer and returns its output,
+// or the error text if rendering failed
+func renderToString(render func(io.Writer) error) string {
+	var b strings.Builder
+	if err := render(&b); err != nil {
+		return err.Error()
+	}
+	return b.String()
+}
+
+// tableGob is the exported proxy used for gob encoding of a Table.
+// The style travels as JSON because CustomFormat holds functions, which
+// gob cannot encode; custom formatters and row filters are not preserved.
+type tableGob struct {
+	FieldNames  []string
+	Rows        [][]any
+	Alignments  map[string]Alignment
+	SortBy      string
+	ReverseSort bool
+	Style       []byte
+	NullString  *string
+}
+
+// GobEncode implements gob.GobEncoder.
+// Cell values of non-builtin types must be registered with gob.Register.
+func (t *Table) GobEncode() ([]byte, error) {
+	style, err := json.Marshal(t.style)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = gob.NewEncoder(&buf).Encode(tableGob{
+		FieldNames:  t.fieldNames,
+		Rows:        t.rows,
+		Alignments:  t.alignments,
+		SortBy:      t.sortBy,
+		ReverseSort: t.reverseSort,
+		Style:       style,
+		NullString:  t.nullString,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder.
+func (t *Table) GobDecode(data []byte) error {
+	var g tableGob
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&g); err != nil {
+		return err
+	}
+	var style TableStyle
+	if err := json.Unmarshal(g.Style, &style); err != nil {
+		return err
 	}
+	t.fieldNames = g.FieldNames
+	t.rows = g.Rows
+	t.alignments = g.Alignments
+	t.sortBy = g.SortBy
+	t.reverseSort = g.ReverseSort
+	t.style = style
+	t.nullString = g.NullString
+	t.InvalidateCache()
+	return nil
 }
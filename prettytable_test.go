@@ -1,9 +1,20 @@
 package prettytable
 
 import (
+	"bytes"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
@@ -406,3 +417,2837 @@ func TestRenderMarkdown(t *testing.T) {
 		}
 	}
 }
+
+func TestGobRoundTrip(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{"foo", 1})
+	table.AddRow([]any{"bar", 2.5})
+	table.SetAlign("B", AlignRight)
+	table.SetSortBy("A", false)
+	table.SetStyle(TableStyle{PaddingWidth: 2, VerticalChar: "."})
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(table); err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	var decoded *Table
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if decoded.RenderASCII() != table.RenderASCII() {
+		t.Errorf("round trip mismatch.\nExpected:\n%s\nActual:\n%s", table.RenderASCII(), decoded.RenderASCII())
+	}
+	if decoded.alignments["B"] != AlignRight {
+		t.Errorf("alignment not preserved: %+v", decoded.alignments)
+	}
+	if decoded.style.PaddingWidth != 2 || decoded.style.VerticalChar != "." {
+		t.Errorf("style not preserved: %+v", decoded.style)
+	}
+}
+
+func TestRenderNDJSON(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{"foo", 1})
+	table.AddRow([]any{"bar", 2})
+
+	expected := "{\"A\":\"foo\",\"B\":1}\n{\"A\":\"bar\",\"B\":2}\n"
+	if actual := table.RenderNDJSON(); actual != expected {
+		t.Errorf("NDJSON output mismatch.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestHeadAndTail(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{"foo", 2})
+	table.AddRow([]any{"bar", 1})
+	table.AddRow([]any{"baz", 3})
+	table.SetSortBy("B", false)
+
+	head := table.Head(2)
+	if len(head.rows) != 2 || head.rows[0][0] != "bar" || head.rows[1][0] != "foo" {
+		t.Errorf("Head(2) returned wrong rows: %+v", head.rows)
+	}
+	tail := table.Tail(1)
+	if len(tail.rows) != 1 || tail.rows[0][0] != "baz" {
+		t.Errorf("Tail(1) returned wrong rows: %+v", tail.rows)
+	}
+	if all := table.Head(10); len(all.rows) != 3 {
+		t.Errorf("Head(10) should return all rows, got %d", len(all.rows))
+	}
+	if all := table.Tail(10); len(all.rows) != 3 {
+		t.Errorf("Tail(10) should return all rows, got %d", len(all.rows))
+	}
+	head.rows[0][0] = "changed"
+	if table.rows[1][0] != "bar" {
+		t.Error("Head should not share row data with the original table")
+	}
+}
+
+func TestDropColumns(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B", "C"})
+	table.AddRow([]any{1, 2, 3})
+	table.AddRow([]any{4, 5, 6})
+	table.SetAlign("A", AlignRight)
+
+	dropped, err := table.DropColumns([]string{"A", "C"})
+	if err != nil {
+		t.Fatalf("DropColumns error: %v", err)
+	}
+	if len(dropped.fieldNames) != 1 || dropped.fieldNames[0] != "B" {
+		t.Errorf("DropColumns returned wrong fields: %+v", dropped.fieldNames)
+	}
+	if dropped.rows[0][0] != 2 || dropped.rows[1][0] != 5 || len(dropped.rows[0]) != 1 {
+		t.Errorf("DropColumns returned wrong rows: %+v", dropped.rows)
+	}
+	if _, ok := dropped.alignments["A"]; ok {
+		t.Error("DropColumns should drop alignments of removed columns")
+	}
+	if len(table.fieldNames) != 3 || len(table.rows[0]) != 3 {
+		t.Errorf("DropColumns mutated the original table: %+v %+v", table.fieldNames, table.rows)
+	}
+
+	_, err = table.DropColumns([]string{"X", "B", "Y"})
+	if err == nil || !strings.Contains(err.Error(), `"X"`) || !strings.Contains(err.Error(), `"Y"`) {
+		t.Errorf("expected error listing all unknown columns, got %v", err)
+	}
+}
+
+func TestRenameColumn(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{"foo", 2})
+	table.AddRow([]any{"bar", 1})
+	table.SetAlign("B", AlignRight)
+	table.SetSortBy("B", false)
+
+	if err := table.RenameColumn("B", "Count"); err != nil {
+		t.Fatalf("RenameColumn error: %v", err)
+	}
+	expected := `+-----+-------+
+| A   | Count |
++-----+-------+
+| bar |     1 |
+| foo |     2 |
++-----+-------+`
+	actual := strings.TrimSpace(table.RenderASCII())
+	if actual != expected {
+		t.Errorf("Rename failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	if err := table.RenameColumn("Z", "Y"); err == nil {
+		t.Error("expected error for missing column name")
+	}
+	if err := table.RenameColumn("A", "Count"); err == nil {
+		t.Error("expected error for duplicate column name")
+	}
+}
+
+func TestReorderColumns(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B", "C"})
+	table.AddRow([]any{1, 2, 3})
+	table.AddRow([]any{4, 5, 6})
+
+	if err := table.ReorderColumns([]string{"C", "A", "B"}); err != nil {
+		t.Fatalf("ReorderColumns error: %v", err)
+	}
+	if strings.Join(table.fieldNames, ",") != "C,A,B" {
+		t.Errorf("ReorderColumns did not reorder fields: %+v", table.fieldNames)
+	}
+	if table.rows[0][0] != 3 || table.rows[0][1] != 1 || table.rows[1][2] != 5 {
+		t.Errorf("ReorderColumns did not reorder rows: %+v", table.rows)
+	}
+
+	// Error cases leave the table untouched
+	for _, fields := range [][]string{{"A", "B"}, {"A", "A", "B"}, {"A", "B", "Z"}} {
+		if err := table.ReorderColumns(fields); err == nil {
+			t.Errorf("expected error for %v", fields)
+		}
+	}
+	if strings.Join(table.fieldNames, ",") != "C,A,B" {
+		t.Errorf("failed ReorderColumns mutated fields: %+v", table.fieldNames)
+	}
+}
+
+func TestInsertRow(t *testing.T) {
+	table := NewTableWithFields([]string{"A"})
+	table.AddRow([]any{"b"})
+	if err := table.InsertRow(0, []any{"a"}); err != nil {
+		t.Fatalf("InsertRow error: %v", err)
+	}
+	if err := table.InsertRow(2, []any{"d"}); err != nil {
+		t.Fatalf("InsertRow error: %v", err)
+	}
+	if err := table.InsertRow(2, []any{"c"}); err != nil {
+		t.Fatalf("InsertRow error: %v", err)
+	}
+	var got []string
+	for _, row := range table.rows {
+		got = append(got, row[0].(string))
+	}
+	if strings.Join(got, "") != "abcd" {
+		t.Errorf("InsertRow produced wrong order: %v", got)
+	}
+
+	if err := table.InsertRow(5, []any{"x"}); err == nil {
+		t.Error("expected error for out-of-range index")
+	}
+	if err := table.InsertRow(-1, []any{"x"}); err == nil {
+		t.Error("expected error for negative index")
+	}
+	if err := table.InsertRow(0, []any{"x", "y"}); err == nil {
+		t.Error("expected error for wrong column count")
+	}
+}
+
+func TestInsertColumn(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{"foo", 1})
+	table.AddRow([]any{"bar", 2})
+
+	if err := table.InsertColumn(0, "ID", []any{10, 20}); err != nil {
+		t.Fatalf("InsertColumn error: %v", err)
+	}
+	if err := table.InsertColumn(2, "X", []any{"x1", "x2"}); err != nil {
+		t.Fatalf("InsertColumn error: %v", err)
+	}
+	expected := `+----+-----+----+---+
+| ID | A   | X  | B |
++----+-----+----+---+
+| 10 | foo | x1 | 1 |
+| 20 | bar | x2 | 2 |
++----+-----+----+---+`
+	actual := strings.TrimSpace(table.RenderASCII())
+	if actual != expected {
+		t.Errorf("InsertColumn failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	if err := table.InsertColumn(5, "Y", []any{1, 2}); err == nil {
+		t.Error("expected error for out-of-range index")
+	}
+	if err := table.InsertColumn(0, "Y", []any{1}); err == nil {
+		t.Error("expected error for wrong column length")
+	}
+}
+
+func TestSwapRowsAndColumns(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{1, 2})
+	table.AddRow([]any{3, 4})
+
+	if err := table.SwapRows(0, 1); err != nil {
+		t.Fatalf("SwapRows error: %v", err)
+	}
+	if table.rows[0][0] != 3 || table.rows[1][0] != 1 {
+		t.Errorf("SwapRows did not swap rows: %+v", table.rows)
+	}
+	if err := table.SwapColumns("A", "B"); err != nil {
+		t.Fatalf("SwapColumns error: %v", err)
+	}
+	if table.fieldNames[0] != "B" || table.rows[0][0] != 4 || table.rows[1][1] != 1 {
+		t.Errorf("SwapColumns did not swap columns: %+v %+v", table.fieldNames, table.rows)
+	}
+
+	// Equal arguments are no-ops
+	if err := table.SwapRows(1, 1); err != nil {
+		t.Errorf("SwapRows with equal indices returned error: %v", err)
+	}
+	if err := table.SwapColumns("A", "A"); err != nil {
+		t.Errorf("SwapColumns with equal names returned error: %v", err)
+	}
+
+	if err := table.SwapRows(0, 2); err == nil {
+		t.Error("expected error for out-of-range row index")
+	}
+	if err := table.SwapColumns("A", "Z"); err == nil {
+		t.Error("expected error for missing column name")
+	}
+}
+
+func TestFindRow(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{"foo", 2})
+	table.AddRow([]any{"bar", 1})
+	table.AddRow([]any{"baz", 3})
+	table.SetSortBy("B", true)
+
+	idx, row, ok := table.FindRow(func(row []any) bool { return row[1].(int) < 3 })
+	if !ok || idx != 1 || row[0] != "foo" {
+		t.Errorf("FindRow = %d, %v, %v; want 1, [foo 2], true", idx, row, ok)
+	}
+	if _, _, ok := table.FindRow(func(row []any) bool { return row[1].(int) > 5 }); ok {
+		t.Error("FindRow should report no match")
+	}
+
+	all := table.FindAllRows(func(row []any) bool { return strings.HasPrefix(row[0].(string), "ba") })
+	if len(all) != 2 || all[0][0] != "baz" || all[1][0] != "bar" {
+		t.Errorf("FindAllRows returned wrong rows: %+v", all)
+	}
+}
+
+func TestDeduplicate(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{"foo", 1})
+	table.AddRow([]any{"bar", 2})
+	table.AddRow([]any{"foo", 1})
+	table.AddRow([]any{"foo", 3})
+
+	deduped, stats, err := table.DeduplicateWithStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deduped.rows) != 3 || stats.Removed != 1 || stats.Kept != 3 {
+		t.Errorf("Deduplicate() kept %d rows, stats %+v; want 3 rows, 1 removed", len(deduped.rows), stats)
+	}
+	byA := table.Deduplicate("A")
+	if len(byA.rows) != 2 || byA.rows[0][1] != 1 || byA.rows[1][0] != "bar" {
+		t.Errorf("Deduplicate(\"A\") returned wrong rows: %+v", byA.rows)
+	}
+	if len(table.rows) != 4 {
+		t.Errorf("Deduplicate mutated the original table: %+v", table.rows)
+	}
+	if _, _, err := table.DeduplicateWithStats("A", "Missing"); err == nil {
+		t.Error("expected error for unknown key field")
+	}
+	if unchanged := table.Deduplicate("Missing"); len(unchanged.rows) != 4 {
+		t.Errorf("Deduplicate with an unknown key removed rows: %+v", unchanged.rows)
+	}
+}
+
+func TestUniqueValues(t *testing.T) {
+	table := NewTableWithFields([]string{"City", "Country"})
+	table.AddRow([]any{"Berlin", "DE"})
+	table.AddRow([]any{"Paris", "FR"})
+	table.AddRow([]any{"Munich", "DE"})
+
+	values, err := table.UniqueValues("Country")
+	if err != nil {
+		t.Fatalf("UniqueValues error: %v", err)
+	}
+	if len(values) != 2 || values[0] != "DE" || values[1] != "FR" {
+		t.Errorf("UniqueValues = %v, want [DE FR]", values)
+	}
+	if n, err := table.UniqueCount("City"); err != nil || n != 3 {
+		t.Errorf("UniqueCount = %d, %v; want 3, nil", n, err)
+	}
+	if _, err := table.UniqueValues("Z"); err == nil {
+		t.Error("expected error for missing column name")
+	}
+}
+
+func TestSortByCustom(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{"foo", 10})
+	table.AddRow([]any{"bar", 9})
+	table.AddRow([]any{"baz", 100})
+
+	table.SortByCustom("B", func(a, b any) bool { return a.(int) < b.(int) })
+	expected := `+-----+-----+
+| A   | B   |
++-----+-----+
+| bar | 9   |
+| foo | 10  |
+| baz | 100 |
++-----+-----+`
+	actual := strings.TrimSpace(table.RenderASCII())
+	if actual != expected {
+		t.Errorf("Custom sort failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	table.SetSortBy("B", true)
+	if _, row, _ := table.FindRow(func([]any) bool { return true }); row[0] != "baz" {
+		t.Errorf("reversed custom sort should start with baz, got %v", row)
+	}
+}
+
+func TestReverse(t *testing.T) {
+	table := NewTableWithFields([]string{"A"})
+	table.AddRow([]any{1})
+	table.AddRow([]any{2})
+	table.AddRow([]any{3})
+
+	reversed := table.ReversedCopy()
+	if reversed.rows[0][0] != 3 || reversed.rows[2][0] != 1 {
+		t.Errorf("ReversedCopy returned wrong order: %+v", reversed.rows)
+	}
+	if table.rows[0][0] != 1 {
+		t.Errorf("ReversedCopy mutated the original table: %+v", table.rows)
+	}
+	table.Reverse()
+	if table.rows[0][0] != 3 || table.rows[1][0] != 2 || table.rows[2][0] != 1 {
+		t.Errorf("Reverse returned wrong order: %+v", table.rows)
+	}
+}
+
+func TestSample(t *testing.T) {
+	table := NewTableWithFields([]string{"A"})
+	for i := 0; i < 20; i++ {
+		table.AddRow([]any{i})
+	}
+
+	s1, err := table.Sample(5, 42)
+	if err != nil {
+		t.Fatalf("Sample error: %v", err)
+	}
+	s2, _ := table.Sample(5, 42)
+	if len(s1.rows) != 5 || s1.RenderCSV() != s2.RenderCSV() {
+		t.Errorf("Sample with the same seed should be deterministic:\n%s\n%s", s1.RenderCSV(), s2.RenderCSV())
+	}
+	seen := make(map[any]bool)
+	for _, row := range s1.rows {
+		if seen[row[0]] {
+			t.Errorf("Sample returned duplicate row %v", row)
+		}
+		seen[row[0]] = true
+	}
+	if all, _ := table.Sample(100, 1); len(all.rows) != 20 {
+		t.Errorf("Sample(100) should return all 20 rows, got %d", len(all.rows))
+	}
+	if _, err := table.Sample(0, 1); err == nil {
+		t.Error("expected error for zero sample size")
+	}
+}
+
+func TestCountAccessors(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	if !table.IsEmpty() || table.ColCount() != 2 {
+		t.Errorf("new table: IsEmpty=%v ColCount=%d", table.IsEmpty(), table.ColCount())
+	}
+	table.AddRow([]any{"foo", 1})
+	table.AddRow([]any{"bar", 2})
+	table.AddRow([]any{"baz", 3})
+	table.SetRowFilter(func(row []any) bool { return row[1].(int) > 1 })
+	if table.RowCount() != 2 || table.StoredRowCount() != 3 || table.IsEmpty() {
+		t.Errorf("RowCount=%d StoredRowCount=%d IsEmpty=%v; want 2, 3, false",
+			table.RowCount(), table.StoredRowCount(), table.IsEmpty())
+	}
+	table.SetRowFilter(func([]any) bool { return false })
+	if !table.IsEmpty() {
+		t.Error("IsEmpty should be true when all rows are filtered out")
+	}
+}
+
+func TestRowsAndSetRows(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	if err := table.SetRows([][]any{{"foo", 1}, {"bar", 2}}); err != nil {
+		t.Fatalf("SetRows error: %v", err)
+	}
+	rows := table.Rows()
+	if len(rows) != 2 || rows[1][0] != "bar" {
+		t.Errorf("Rows() = %+v", rows)
+	}
+	rows[0][0] = "changed"
+	if table.rows[0][0] != "foo" {
+		t.Error("Rows() should return a copy")
+	}
+	if err := table.SetRows([][]any{{"baz", 3}, {"short"}}); err == nil {
+		t.Error("expected error for wrong column count")
+	}
+	if len(table.rows) != 2 || table.rows[0][0] != "foo" {
+		t.Errorf("failed SetRows mutated the table: %+v", table.rows)
+	}
+}
+
+func TestRenderWithRowNumbers(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{"foo", 2})
+	table.AddRow([]any{"bar", 1})
+	table.SetSortBy("B", false)
+
+	expected := `+---+-----+---+
+| # | A   | B |
++---+-----+---+
+| 1 | bar | 1 |
+| 2 | foo | 2 |
++---+-----+---+`
+	actual := strings.TrimSpace(table.RenderWithRowNumbers(1))
+	if actual != expected {
+		t.Errorf("Row numbers failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	table.SetRowNumberLabel("No")
+	table.SetAlign("No", AlignRight)
+	expected = `+----+-----+---+
+| No | A   | B |
++----+-----+---+
+|  0 | bar | 1 |
+|  1 | foo | 2 |
++----+-----+---+`
+	actual = strings.TrimSpace(table.RenderWithRowNumbers(0))
+	if actual != expected {
+		t.Errorf("Row numbers with label failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+	if len(table.fieldNames) != 2 {
+		t.Errorf("RenderWithRowNumbers mutated the table: %+v", table.fieldNames)
+	}
+
+	table.SetRowNumberLabel("A")
+	if err := table.RenderWithRowNumbersToWriter(io.Discard, 1); err == nil || err.Error() != `column "A" already exists` {
+		t.Errorf("expected an error for a label matching a field, got %v", err)
+	}
+}
+
+func TestSetDataSeparator(t *testing.T) {
+	table := NewTableWithFields([]string{"A"})
+	for i := 1; i <= 5; i++ {
+		table.AddRow([]any{i})
+	}
+	table.SetDataSeparator(2)
+	expected := `+---+
+| A |
++---+
+| 1 |
+| 2 |
++---+
+| 3 |
+| 4 |
++---+
+| 5 |
++---+`
+	actual := strings.TrimSpace(table.RenderASCII())
+	if actual != expected {
+		t.Errorf("Data separator failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	table.DelRow(4)
+	table.SetDataSeparator(1)
+	expected = `┌───┐
+│ A │
+├───┤
+│ 1 │
+├───┤
+│ 2 │
+├───┤
+│ 3 │
+├───┤
+│ 4 │
+└───┘`
+	actual = strings.TrimSpace(table.RenderUnicode())
+	if actual != expected {
+		t.Errorf("Unicode data separator failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestAddRowMapAndGetRowMap(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	if err := table.AddRowMap(map[string]any{"B": 1, "A": "foo", "extra": true}); err != nil {
+		t.Fatalf("AddRowMap error: %v", err)
+	}
+	if err := table.AddRowMap(map[string]any{"A": "bar"}); err == nil {
+		t.Error("expected error for missing field")
+	}
+	if err := table.AddRowMapPartial(map[string]any{"A": "bar"}); err != nil {
+		t.Fatalf("AddRowMapPartial error: %v", err)
+	}
+	if len(table.rows) != 2 || table.rows[0][0] != "foo" || table.rows[0][1] != 1 || table.rows[1][1] != nil {
+		t.Errorf("AddRowMap stored wrong rows: %+v", table.rows)
+	}
+
+	m, err := table.GetRowMap(0)
+	if err != nil {
+		t.Fatalf("GetRowMap error: %v", err)
+	}
+	if len(m) != 2 || m["A"] != "foo" || m["B"] != 1 {
+		t.Errorf("GetRowMap(0) = %v", m)
+	}
+	if _, err := table.GetRowMap(2); err == nil {
+		t.Error("expected error for out-of-range row index")
+	}
+}
+
+func TestFromSliceOfMaps(t *testing.T) {
+	data := []map[string]any{
+		{"name": "Adelaide", "area": 1295},
+		{"name": "Darwin", "population": 120900},
+	}
+	table, err := FromSliceOfMaps(data)
+	if err != nil {
+		t.Fatalf("FromSliceOfMaps error: %v", err)
+	}
+	if strings.Join(table.fieldNames, ",") != "area,name,population" {
+		t.Errorf("FromSliceOfMaps fields = %v, want [area name population]", table.fieldNames)
+	}
+	if table.rows[1][0] != nil || table.rows[1][1] != "Darwin" {
+		t.Errorf("FromSliceOfMaps rows = %+v", table.rows)
+	}
+
+	if _, err := FromSliceOfMapsWithOptions(data, MapOptions{StrictKeys: true}); err == nil {
+		t.Error("expected error for differing keys with StrictKeys")
+	}
+}
+
+func TestRenderFixed(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Note"})
+	table.AddRow([]any{"Adelaide", "hot and dry"})
+	table.AddRow([]any{"Darwin", "wet"})
+	table.SetStyle(TableStyle{TruncationMarker: "~"})
+
+	expected := `+------------+-------------+
+| Name       | Note        |
++------------+-------------+
+| Adelaide   | hot and dry |
+| Darwin     | wet         |
++------------+-------------+`
+	actual := strings.TrimSpace(table.RenderFixed(map[string]int{"Name": 10, "Unknown": 3}))
+	if actual != expected {
+		t.Errorf("Fixed padding failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	expected = `+-------+-------------+
+| Name  | Note        |
++-------+-------------+
+| Adel~ | hot and dry |
+| Darw~ | wet         |
++-------+-------------+`
+	actual = strings.TrimSpace(table.RenderFixed(map[string]int{"Name": 5}))
+	if actual != expected {
+		t.Errorf("Fixed truncation failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestEqualizeColumnWidths(t *testing.T) {
+	table := NewTableWithFields([]string{"Mo", "Tu", "Wednesday"})
+	table.AddRow([]any{1, 2, 3})
+	table.EqualizeColumnWidths()
+	expected := `+-----------+-----------+-----------+
+| Mo        | Tu        | Wednesday |
++-----------+-----------+-----------+
+| 1         | 2         | 3         |
++-----------+-----------+-----------+`
+	actual := strings.TrimSpace(table.RenderASCII())
+	if actual != expected {
+		t.Errorf("Equalize failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	table.ResetColumnWidths()
+	expected = `+----+----+-----------+
+| Mo | Tu | Wednesday |
++----+----+-----------+
+| 1  | 2  | 3         |
++----+----+-----------+`
+	actual = strings.TrimSpace(table.RenderASCII())
+	if actual != expected {
+		t.Errorf("Reset failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestColumnMaxWidthAndFit(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Description"})
+	table.AddRow([]any{"Adelaide", "Capital of South Australia"})
+	table.SetColumnMaxWidth("Description", 10)
+	expected := `+----------+------------+
+| Name     | Descriptio |
++----------+------------+
+| Adelaide | Capital of |
++----------+------------+`
+	actual := strings.TrimSpace(table.RenderASCII())
+	if actual != expected {
+		t.Errorf("Max width failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	if err := table.fitWidth(30); err != nil {
+		t.Fatalf("fitWidth error: %v", err)
+	}
+	for _, line := range strings.Split(table.RenderASCII(), "\n") {
+		if runeWidth(line) > 30 {
+			t.Errorf("line wider than 30: %q", line)
+		}
+	}
+	table.SetStyle(TableStyle{PaddingWidth: 3})
+	table.SetColumnPadding("Name", 0, 4)
+	if err := table.fitWidth(30); err != nil {
+		t.Fatalf("fitWidth error: %v", err)
+	}
+	for _, line := range strings.Split(table.RenderASCII(), "\n") {
+		if runeWidth(line) > 30 {
+			t.Errorf("line wider than 30 with padding: %q", line)
+		}
+	}
+	table.SetStyle(TableStyle{})
+	table.SetColumnPadding("Name", 1, 1)
+	if err := table.fitWidth(80); err != nil || table.maxWidths["Description"] > 10 {
+		t.Errorf("fitWidth(80) should keep the Description limit, got %v, %v", err, table.maxWidths)
+	}
+	if err := table.fitWidth(5); err == nil {
+		t.Error("expected error for too narrow width")
+	}
+}
+
+func TestSetNullString(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{"foo", nil})
+	if table.GetNullString() != DefaultNullString {
+		t.Errorf("GetNullString() = %q, want default %q", table.GetNullString(), DefaultNullString)
+	}
+	expected := `+-----+---+
+| A   | B |
++-----+---+
+| foo |   |
++-----+---+`
+	actual := strings.TrimSpace(table.RenderASCII())
+	if actual != expected {
+		t.Errorf("Default null string failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	table.SetNullString("NULL")
+	expected = `+-----+------+
+| A   | B    |
++-----+------+
+| foo | NULL |
++-----+------+`
+	actual = strings.TrimSpace(table.RenderASCII())
+	if actual != expected {
+		t.Errorf("Null string failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+	if csv := table.RenderCSV(); !strings.Contains(csv, "foo,NULL") {
+		t.Errorf("CSV output should use null string: %s", csv)
+	}
+	if html := table.RenderHTML(); !strings.Contains(html, "<td>NULL</td>") {
+		t.Errorf("HTML output should use null string: %s", html)
+	}
+}
+
+func TestSetBoolStrings(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{true, false})
+	if csv := table.RenderCSV(); !strings.Contains(csv, "true,false") {
+		t.Errorf("bools should render with %%v by default: %s", csv)
+	}
+	table.SetBoolStrings("Yes", "No")
+	table.SetColumnBoolStrings("B", "✓", "✗")
+	expected := `┌─────┬───┐
+│ A   │ B │
+├─────┼───┤
+│ Yes │ ✗ │
+└─────┴───┘`
+	actual := strings.TrimSpace(table.RenderUnicode())
+	if actual != expected {
+		t.Errorf("Bool strings failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestToWriter(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{"foo", 1})
+	table.AddRow([]any{"bar", 2})
+
+	writers := map[string]func(io.Writer) error{
+		"ascii":     table.RenderASCIIToWriter,
+		"unicode":   table.RenderUnicodeToWriter,
+		"csv":       table.RenderCSVToWriter,
+		"json":      table.RenderJSONToWriter,
+		"ndjson":    table.RenderNDJSONToWriter,
+		"html":      table.RenderHTMLToWriter,
+		"latex":     table.RenderLaTeXToWriter,
+		"mediawiki": table.RenderMediaWikiToWriter,
+		"markdown":  table.RenderMarkdownToWriter,
+	}
+	for format, write := range writers {
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			t.Errorf("%s: unexpected error: %v", format, err)
+		}
+		if buf.String() != table.GetFormattedString(format) {
+			t.Errorf("%s: writer output differs from string output:\n%s\n%s", format, buf.String(), table.GetFormattedString(format))
+		}
+		buf.Reset()
+		if err := table.WriteFormatted(&buf, format); err != nil || buf.String() != table.GetFormattedString(format) {
+			t.Errorf("%s: WriteFormatted mismatch (err %v)", format, err)
+		}
+		if err := write(failingWriter{}); err == nil {
+			t.Errorf("%s: expected error from failing writer", format)
+		}
+	}
+}
+
+func TestRenderPSQL(t *testing.T) {
+	table := NewTableWithFields([]string{"id", "name"})
+	table.AddRow([]any{1, "alice"})
+	table.AddRow([]any{22, "bob"})
+	table.SetAlign("id", AlignRight)
+
+	// The header keeps its trailing padding; data rows are trimmed
+	expected := " id | name  \n" +
+		"----+-------\n" +
+		"  1 | alice\n" +
+		" 22 | bob"
+	if actual := table.RenderPSQL(); actual != expected {
+		t.Errorf("PSQL output mismatch.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	table.SetStyle(TableStyle{PSQLRowCount: true})
+	if actual := table.RenderPSQL(); !strings.HasSuffix(actual, "\n(2 rows)") {
+		t.Errorf("PSQL output should end with row count:\n%s", actual)
+	}
+	table.DelRow(1)
+	if actual := table.RenderPSQL(); !strings.HasSuffix(actual, "\n(1 row)") {
+		t.Errorf("PSQL output should end with singular row count:\n%s", actual)
+	}
+}
+
+func TestRenderMySQL(t *testing.T) {
+	table := NewTableWithFields([]string{"id", "name"})
+	table.AddRow([]any{1, "alice"})
+	table.AddRow([]any{2, "bob"})
+	table.SetAlign("id", AlignRight)
+	table.SetStyle(TableStyle{PaddingWidth: 3, HorizontalChar: "=", TruncationMarker: "~"})
+
+	expected := `+----+-------+
+| id | name  |
++----+-------+
+|  1 | alice |
+|  2 | bob   |
++----+-------+`
+	if actual := table.RenderMySQL(); actual != expected {
+		t.Errorf("MySQL output mismatch.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestRenderSimple(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "N"})
+	table.AddRow([]any{"foo", 1})
+	table.AddRow([]any{"barbaz", 22})
+	table.SetAlign("N", AlignRight)
+
+	expected := "Name     N\n" +
+		"------  --\n" +
+		"foo      1\n" +
+		"barbaz  22"
+	if actual := table.RenderSimple(); actual != expected {
+		t.Errorf("Simple output mismatch.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestRenderCompact(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "Num"})
+	table.AddRow([]any{"foo", 1})
+	table.AddRow([]any{"bar", 22})
+	table.SetAlign("Num", AlignRight)
+
+	expected := `+---+---+
+|A  |Num|
++---+---+
+|foo|  1|
+|bar| 22|
++---+---+`
+	if actual := table.RenderCompact(); actual != expected {
+		t.Errorf("Compact output mismatch.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestSetCaption(t *testing.T) {
+	table := NewTableWithFields([]string{"A"})
+	table.AddRow([]any{1})
+
+	table.SetCaption("Cities & towns", false)
+	if table.GetCaption() != "Cities & towns" {
+		t.Errorf("GetCaption() = %q", table.GetCaption())
+	}
+	if ascii := table.RenderASCII(); !strings.HasPrefix(ascii, "Cities & towns\n+---+") {
+		t.Errorf("ASCII caption should come first:\n%s", ascii)
+	}
+	if html := table.RenderHTML(); !strings.Contains(html, "<table border=\"1\">\n<caption>Cities &amp; towns</caption>\n<tr>") {
+		t.Errorf("HTML caption missing:\n%s", html)
+	}
+	latex := table.RenderLaTeX()
+	if !strings.HasPrefix(latex, "\\begin{table}\n\\caption{Cities \\& towns}\n\\begin{tabular}") || !strings.HasSuffix(latex, "\\end{table}") {
+		t.Errorf("LaTeX caption missing:\n%s", latex)
+	}
+
+	table.SetCaption("Below", true)
+	if unicode := table.RenderUnicode(); !strings.HasSuffix(unicode, "┘\nBelow") {
+		t.Errorf("Unicode caption should come last:\n%s", unicode)
+	}
+	if html := table.RenderHTML(); !strings.Contains(html, `<caption style="caption-side: bottom">Below</caption>`) {
+		t.Errorf("HTML caption should be placed below:\n%s", html)
+	}
+	if latex := table.RenderLaTeX(); !strings.HasSuffix(latex, "\\end{tabular}\n\\caption{Below}\n\\end{table}") {
+		t.Errorf("LaTeX caption should be placed below:\n%s", latex)
+	}
+
+	table.ClearCaption()
+	if strings.Contains(table.RenderASCII(), "Below") || strings.Contains(table.RenderLaTeX(), "table}") {
+		t.Error("ClearCaption should remove the caption")
+	}
+}
+
+func TestFillNil(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{nil, 1})
+	table.AddRow([]any{"foo", nil})
+	table.AddRow([]any{nil, nil})
+
+	n, err := table.FillNil("A", "-")
+	if err != nil || n != 2 {
+		t.Errorf("FillNil = %d, %v; want 2, nil", n, err)
+	}
+	if table.rows[0][0] != "-" || table.rows[1][0] != "foo" || table.rows[2][1] != nil {
+		t.Errorf("FillNil replaced wrong cells: %+v", table.rows)
+	}
+	if n := table.FillAllNils(0); n != 2 || table.rows[1][1] != 0 || table.rows[2][1] != 0 {
+		t.Errorf("FillAllNils = %d, rows %+v", n, table.rows)
+	}
+	if _, err := table.FillNil("Z", 0); err == nil {
+		t.Error("expected error for missing column name")
+	}
+}
+
+func TestMapColumn(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{" foo ", 1})
+	table.AddRow([]any{"bar  ", 2})
+
+	trim := func(v any) any { return strings.TrimSpace(v.(string)) }
+	copied, err := table.MapColumnCopy("A", trim)
+	if err != nil {
+		t.Fatalf("MapColumnCopy error: %v", err)
+	}
+	if copied.rows[0][0] != "foo" || table.rows[0][0] != " foo " {
+		t.Errorf("MapColumnCopy: copy %+v, original %+v", copied.rows, table.rows)
+	}
+	if err := table.MapColumn("A", trim); err != nil {
+		t.Fatalf("MapColumn error: %v", err)
+	}
+	if table.rows[0][0] != "foo" || table.rows[1][0] != "bar" || table.rows[1][1] != 2 {
+		t.Errorf("MapColumn did not transform column: %+v", table.rows)
+	}
+	if err := table.MapColumn("Z", trim); err == nil {
+		t.Error("expected error for missing column name")
+	}
+}
+
+func TestJoin(t *testing.T) {
+	cities := NewTableWithFields([]string{"City", "Country", "Name"})
+	cities.AddRow([]any{"Berlin", "DE", "b"})
+	cities.AddRow([]any{"Paris", "FR", "p"})
+	cities.AddRow([]any{"Rome", "IT", "r"})
+	countries := NewTableWithFields([]string{"Code", "Name"})
+	countries.AddRow([]any{"DE", "Germany"})
+	countries.AddRow([]any{"FR", "France"})
+
+	inner, err := cities.InnerJoin(countries, "Country", "Code")
+	if err != nil {
+		t.Fatalf("InnerJoin error: %v", err)
+	}
+	expected := `+--------+---------+------+------------+
+| City   | Country | Name | right_Name |
++--------+---------+------+------------+
+| Berlin | DE      | b    | Germany    |
+| Paris  | FR      | p    | France     |
++--------+---------+------+------------+`
+	if actual := inner.RenderASCII(); actual != expected {
+		t.Errorf("InnerJoin failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	left, err := cities.Join(countries, "Country", "Code", JoinOptions{Left: true, CollisionPrefix: "country_"})
+	if err != nil {
+		t.Fatalf("Join error: %v", err)
+	}
+	if len(left.rows) != 3 || left.fieldNames[3] != "country_Name" || left.rows[2][3] != nil {
+		t.Errorf("LeftJoin returned %v %+v", left.fieldNames, left.rows)
+	}
+
+	if _, err := cities.LeftJoin(countries, "Z", "Code"); err == nil {
+		t.Error("expected error for missing left column")
+	}
+	if _, err := cities.InnerJoin(countries, "Country", "Z"); err == nil {
+		t.Error("expected error for missing right column")
+	}
+}
+
+func TestRenderMarkdownAligned(t *testing.T) {
+	table := NewTableWithFields([]string{"City", "Area", "Code", "X"})
+	table.AddRow([]any{"Adelaide", 1295, "SA", 1})
+	table.AddRow([]any{"Darwin", 112, "NT", 2})
+	table.SetAlign("City", AlignLeft)
+	table.SetAlign("Area", AlignRight)
+	table.SetAlign("Code", AlignCenter)
+
+	expected := `| City     | Area | Code | X   |
+| :------- | ---: | :--: | --- |
+| Adelaide | 1295 |  SA  | 1   |
+| Darwin   |  112 |  NT  | 2   |`
+	if actual := table.RenderMarkdownAligned(); actual != expected {
+		t.Errorf("Aligned Markdown mismatch.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestRenderHTMLDataTable(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Active"})
+	table.AddRow([]any{"a<b", true})
+	table.SetBoolStrings("Yes", "No")
+
+	expected := `<table class="datatable">
+<thead>
+<tr><th data-field="Name">Name</th><th data-field="Active">Active</th></tr>
+</thead>
+<tbody>
+<tr><td data-value="a&lt;b">a&lt;b</td><td data-value="true">Yes</td></tr>
+</tbody>
+</table>`
+	if actual := table.RenderHTMLDataTable(DataTableOptions{}); actual != expected {
+		t.Errorf("DataTable HTML mismatch.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	actual := table.RenderHTMLDataTable(DataTableOptions{Class: "display", OmitFieldAttr: true, OmitValueAttr: true})
+	if strings.Contains(actual, "data-") || !strings.HasPrefix(actual, `<table class="display">`) {
+		t.Errorf("DataTable options not applied:\n%s", actual)
+	}
+}
+
+func TestRenderEmailHTML(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{"foo", 1})
+	table.SetAlign("B", AlignRight)
+
+	html := table.RenderEmailHTML()
+	for _, want := range []string{
+		`<th style="border:1px solid #ccc; border-collapse:collapse; padding:4px; font-family:Arial, Helvetica, sans-serif; text-align:left; background-color:#f2f2f2">A</th>`,
+		`<td style="border:1px solid #ccc; border-collapse:collapse; padding:4px; font-family:Arial, Helvetica, sans-serif; text-align:right">1</td>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("email HTML missing %s:\n%s", want, html)
+		}
+	}
+
+	html = table.RenderEmailHTMLWithTheme(EmailTheme{HeaderBackground: "#003366"})
+	if !strings.Contains(html, "background-color:#003366") || !strings.Contains(html, "border:1px solid #ccc") {
+		t.Errorf("email theme not applied with defaults:\n%s", html)
+	}
+}
+
+func TestRenderRST(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{"foo", 1})
+	table.AddRow([]any{"bar", 2})
+
+	expected := `+-----+---+
+| A   | B |
++=====+===+
+| foo | 1 |
++-----+---+
+| bar | 2 |
++-----+---+`
+	if actual := table.RenderRST(); actual != expected {
+		t.Errorf("RST grid mismatch.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	expected = `===  =
+A    B
+===  =
+foo  1
+bar  2
+===  =`
+	if actual := table.RenderRSTSimple(); actual != expected {
+		t.Errorf("RST simple mismatch.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestRenderAsciiDoc(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Qty"})
+	table.AddRow([]any{"apple", 3})
+	table.AddRow([]any{"a|b", 10})
+	table.SetAlign("Qty", AlignRight)
+
+	expected := `[cols="<62,>38",options="header"]
+|===
+|Name |Qty
+|apple |3
+|a\|b |10
+|===`
+	if actual := table.RenderAsciiDoc(); actual != expected {
+		t.Errorf("AsciiDoc mismatch.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestPivot(t *testing.T) {
+	table := NewTableWithFields([]string{"Country", "Year", "Sales"})
+	table.AddRow([]any{"US", 2023, 100})
+	table.AddRow([]any{"US", 2024, 150})
+	table.AddRow([]any{"DE", 2023, 80})
+	table.AddRow([]any{"US", 2023, 50})
+
+	p, err := table.Pivot("Country", "Year", "Sales", AggSum)
+	if err != nil {
+		t.Fatalf("Pivot failed: %v", err)
+	}
+	expected := `+---------+------+------+
+| Country | 2023 | 2024 |
++---------+------+------+
+| US      | 150  | 150  |
+| DE      | 80   |      |
++---------+------+------+`
+	if actual := p.RenderASCII(); actual != expected {
+		t.Errorf("Pivot failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	p, _ = table.Pivot("Country", "Year", "Sales", AggCount)
+	if rows := p.Rows(); rows[0][1] != 2 || rows[1][2] != nil {
+		t.Errorf("expected counts, got %v", rows)
+	}
+	p, _ = table.Pivot("Country", "Year", "Sales", AggMean)
+	if rows := p.Rows(); rows[0][1] != 75.0 {
+		t.Errorf("expected mean 75, got %v", rows[0][1])
+	}
+	if _, err := table.Pivot("Country", "Month", "Sales", AggSum); err == nil {
+		t.Error("expected error for unknown column")
+	}
+	if _, err := table.Pivot("Year", "Sales", "Country", AggSum); err == nil {
+		t.Error("expected error for non-numeric sum")
+	}
+}
+
+func TestGroupByStats(t *testing.T) {
+	table := NewTableWithFields([]string{"Team", "Score"})
+	table.AddRow([]any{"red", 3})
+	table.AddRow([]any{"blue", 5})
+	table.AddRow([]any{"red", 7})
+	table.AddRow([]any{"blue", nil})
+
+	g, err := table.GroupByStats("Team", []ColumnStat{
+		{Field: "Score", Func: AggSum},
+		{Field: "Score", Func: AggCount, OutputName: "N"},
+		{Field: "Score", Func: AggMin},
+		{Field: "Score", Func: AggMax},
+		{Field: "Score", Func: AggMean, OutputName: "Avg"},
+	})
+	if err != nil {
+		t.Fatalf("GroupByStats failed: %v", err)
+	}
+	expected := `+------+-----------+---+-----------+-----------+-----+
+| Team | Score_sum | N | Score_min | Score_max | Avg |
++------+-----------+---+-----------+-----------+-----+
+| red  | 10        | 2 | 3         | 7         | 5   |
+| blue | 5         | 1 | 5         | 5         | 5   |
++------+-----------+---+-----------+-----------+-----+`
+	if actual := g.RenderASCII(); actual != expected {
+		t.Errorf("GroupByStats failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+	if _, err := table.GroupByStats("Team", []ColumnStat{{Field: "Missing", Func: AggSum}}); err == nil {
+		t.Error("expected error for unknown column")
+	}
+}
+
+func TestFromStructs(t *testing.T) {
+	type person struct {
+		Name   string `table:"Full Name"`
+		Email  string `table:",omitempty"`
+		ID     int    `table:"ID,order=1"`
+		Secret string `table:"-"`
+		age    int
+	}
+	people := []*person{
+		{Name: "Alice", Email: "alice@example.com", ID: 1, Secret: "x", age: 30},
+		{Name: "Bob", ID: 2},
+	}
+	table, err := FromStructs(people)
+	if err != nil {
+		t.Fatalf("FromStructs failed: %v", err)
+	}
+	table.SetNullString("-")
+	expected := `+----+-----------+-------------------+
+| ID | Full Name | Email             |
++----+-----------+-------------------+
+| 1  | Alice     | alice@example.com |
+| 2  | Bob       | -                 |
++----+-----------+-------------------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("FromStructs failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+	if _, err := FromStructs([]int{1}); err == nil {
+		t.Error("expected error for non-struct elements")
+	}
+}
+
+func TestSetColumnFormat(t *testing.T) {
+	table := NewTableWithFields([]string{"ID", "Price", "Name"})
+	table.AddRow([]any{7, 3.5, "tea"})
+	table.AddRow([]any{42, nil, "cake"})
+	table.SetColumnFormat("ID", "%05d")
+	table.SetColumnFormat("Price", "$%.2f")
+	table.SetColumnFormat("Name", "%q")
+	table.SetStyle(TableStyle{CustomFormat: map[string]func(string, any) string{
+		"Name": func(_ string, v any) string { return strings.ToUpper(v.(string)) },
+	}})
+
+	expected := `+-------+-------+------+
+| ID    | Price | Name |
++-------+-------+------+
+| 00007 | $3.50 | TEA  |
+| 00042 |       | CAKE |
++-------+-------+------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("SetColumnFormat failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+	if csv := table.RenderCSV(); !strings.Contains(csv, "00007,$3.50,TEA") {
+		t.Errorf("expected formatted CSV, got %q", csv)
+	}
+}
+
+func TestSetDateTimeFormat(t *testing.T) {
+	table := NewTableWithFields([]string{"Event", "CreatedAt"})
+	table.AddRow([]any{"launch", time.Date(2024, 3, 9, 14, 30, 0, 0, time.UTC)})
+	table.AddRow([]any{"unknown", "n/a"})
+	table.SetDateTimeFormat("CreatedAt", "2006-01-02")
+
+	expected := `+---------+------------+
+| Event   | CreatedAt  |
++---------+------------+
+| launch  | 2024-03-09 |
+| unknown | n/a        |
++---------+------------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("SetDateTimeFormat failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestRenderJSONWithOptions(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Age"})
+	table.AddRow([]any{"Alice", 30})
+	table.AddRow([]any{"Bob", nil})
+
+	expected := `[{"Name":"Alice","Age":30},{"Name":"Bob","Age":null}]`
+	if actual := table.RenderJSONWithOptions(JSONRenderOptions{}); actual != expected {
+		t.Errorf("compact JSON failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	expected = `[
+	{
+		"Age": 30,
+		"Name": "Alice"
+	},
+	{
+		"Age": null,
+		"Name": "Bob"
+	}
+]`
+	if actual := table.RenderJSONWithOptions(JSONRenderOptions{Indent: "\t", SortKeys: true}); actual != expected {
+		t.Errorf("indented JSON failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	expected = `{"fields":["Name","Age"],"rows":[["Alice",30],["Bob",null]]}`
+	if actual := table.RenderJSONWithOptions(JSONRenderOptions{Format: JSONFieldsAndRows}); actual != expected {
+		t.Errorf("fields and rows JSON failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestValidateRows(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Age"})
+	table.AddRow([]any{"Alice", 30})
+	table.AddRow([]any{"", -1})
+	table.AddRow([]any{"Carol", 41})
+
+	errNegative := errors.New("must not be negative")
+	errs := table.ValidateRows(map[string]func(any) error{
+		"Age": func(v any) error {
+			if v.(int) < 0 {
+				return errNegative
+			}
+			return nil
+		},
+		"Name": func(v any) error {
+			if v == "" {
+				return errors.New("must not be empty")
+			}
+			return nil
+		},
+		"Email": func(any) error { return nil },
+	})
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 validation errors, got %v", errs)
+	}
+	if errs[0].RowIndex != -1 || errs[0].Field != "Email" {
+		t.Errorf("expected unknown column error first, got %v", errs[0])
+	}
+	if errs[1].Field != "Name" || errs[2].Field != "Age" || errs[2].Value != -1 {
+		t.Errorf("unexpected validation errors: %v", errs)
+	}
+	if !errors.Is(errs[2], errNegative) {
+		t.Error("expected ValidationError to unwrap to the validator error")
+	}
+	if msg := errs[1].Error(); msg != `row 1, column "Name": must not be empty` {
+		t.Errorf("unexpected message %q", msg)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	ch := make(chan TableEvent, 10)
+	table.Watch(ch)
+
+	table.AddRow([]any{1, nil})
+	table.FillNil("B", 0)
+	table.DelRow(0)
+	table.AddColumn("C", nil)
+	table.Clear()
+
+	want := []EventType{RowAdded, CellUpdated, RowDeleted, ColumnsChanged, Cleared}
+	for i, typ := range want {
+		ev := <-ch
+		if ev.Type != typ {
+			t.Errorf("event %d: expected type %d, got %+v", i, typ, ev)
+		}
+	}
+
+	full := make(chan TableEvent)
+	table.Watch(full)
+	table.Unwatch(ch)
+	table.AddRow([]any{1})
+	if len(ch) != 0 {
+		t.Errorf("expected no events after Unwatch, got %d", len(ch))
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{1, "x"})
+	table.SetAlign("A", AlignRight)
+	snap := table.Snapshot()
+
+	table.AddRow([]any{2, "y"})
+	table.SetAlign("A", AlignLeft)
+	expected := `+---+---+
+| A | B |
++---+---+
+| 1 | x |
++---+---+`
+	if actual := snap.RenderASCII(); actual != expected {
+		t.Errorf("Snapshot failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	if err := snap.AddRow([]any{3, "z"}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly from AddRow, got %v", err)
+	}
+	if err := snap.DelRow(0); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly from DelRow, got %v", err)
+	}
+	if err := snap.AddColumn("C", []any{1}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly from AddColumn, got %v", err)
+	}
+	if err := snap.DelColumn("A"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly from DelColumn, got %v", err)
+	}
+	if err := snap.Clear(); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly from Clear, got %v", err)
+	}
+	if err := snap.SetFieldNames([]string{"X", "Y"}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly from SetFieldNames, got %v", err)
+	}
+	if snap.RowCount() != 1 || table.RowCount() != 2 {
+		t.Errorf("expected 1 snapshot row and 2 table rows, got %d and %d", snap.RowCount(), table.RowCount())
+	}
+
+	table = NewTableWithFields([]string{"A"})
+	table.AddRow([]any{nil})
+	table.SetStyle(TableStyle{ANSIEnabled: true})
+	table.SetRowHighlight(0, CellStyle{Bold: true})
+	table.SetHighlightCondition("A", func(any) bool { return true }, CellStyle{FG: ColorRed})
+	table.SetColumnRequired("A")
+	table.SetDefaultValue("A", 0)
+	snap = table.Snapshot()
+	expected = table.RenderANSI()
+	table.ClearAllHighlights()
+	table.SetHighlightCondition("A", func(any) bool { return true }, CellStyle{FG: ColorGreen})
+	table.SetColumnRequired("B")
+	table.SetDefaultValue("A", 1)
+	if actual := snap.RenderANSI(); actual != expected {
+		t.Errorf("Snapshot highlights failed.\nExpected:\n%q\nActual:\n%q", expected, actual)
+	}
+	if errs := snap.ValidateRequired(); len(errs) != 1 {
+		t.Errorf("expected the snapshot to keep its required column, got %v", errs)
+	}
+	if snap.defaults["A"] != 0 || len(snap.required) != 1 {
+		t.Errorf("expected the snapshot settings to be unchanged, got %v and %v", snap.defaults, snap.required)
+	}
+}
+
+func TestRenderCache(t *testing.T) {
+	table := NewTableWithFields([]string{"A"})
+	table.AddRow([]any{1})
+	table.EnableCache()
+	first := table.RenderASCII()
+	if table.RenderASCII() != first {
+		t.Error("expected cached output to match")
+	}
+
+	table.AddRow([]any{2})
+	if table.RenderASCII() == first {
+		t.Error("expected re-render after AddRow")
+	}
+
+	table.SetRowFilter(func(row []any) bool { return row[0] == 1 })
+	if actual := table.RenderASCII(); actual != first {
+		t.Errorf("expected re-render after SetRowFilter.\nExpected:\n%s\nActual:\n%s", first, actual)
+	}
+
+	table.SetCellAt(0, "A", 3)
+	if actual := table.RenderASCII(); actual == first {
+		t.Error("expected re-render after SetCellAt")
+	}
+	table.SetRowFilter(nil)
+	table.MapColumn("A", func(v any) any { return v.(int) * 10 })
+	if actual := table.RenderASCII(); !strings.Contains(actual, "| 30 |") || !strings.Contains(actual, "| 20 |") {
+		t.Errorf("expected re-render after MapColumn, got\n%s", actual)
+	}
+
+	// Filters built from the same function literal differ only in what
+	// they capture
+	table = NewTableWithFields([]string{"A"})
+	for i := range 3 {
+		table.AddRow([]any{i})
+	}
+	table.EnableCache()
+	for _, lim := range []int{1, 3} {
+		table.SetRowFilter(func(r []any) bool { return r[0].(int) < lim })
+		if actual := table.RenderASCII(); strings.Count(actual, "\n| ") != lim+1 {
+			t.Errorf("expected %d rows with limit %d, got\n%s", lim, lim, actual)
+		}
+	}
+
+	suffix := "!"
+	table = NewTableWithFields([]string{"A"})
+	table.AddRow([]any{1})
+	table.SetStyle(TableStyle{CustomFormat: map[string]func(string, any) string{
+		"A": func(_ string, v any) string { return fmt.Sprint(v) + suffix },
+	}})
+	table.EnableCache()
+	table.RenderASCII()
+	suffix = "?"
+	table.InvalidateCache()
+	if actual := table.RenderASCII(); !strings.Contains(actual, "1?") {
+		t.Errorf("expected re-render after InvalidateCache, got\n%s", actual)
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Age"})
+	table.AddRow([]any{"Bob", 25})
+	table.AddRow([]any{"Alice", 30})
+	table.SetSortBy("Name", false)
+
+	tmpl, err := ParseTemplate(`{{range .Rows}}{{index . 0}} is {{index . 1}}
+{{end}}`)
+	if err != nil {
+		t.Fatalf("ParseTemplate failed: %v", err)
+	}
+	actual, err := table.RenderTemplate(tmpl)
+	if err != nil {
+		t.Fatalf("RenderTemplate failed: %v", err)
+	}
+	expected := "Alice is 30\nBob is 25\n"
+	if actual != expected {
+		t.Errorf("RenderTemplate failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	tmpl, _ = ParseTemplate(`{{.Missing}}`)
+	if _, err := table.RenderTemplate(tmpl); err == nil {
+		t.Error("expected error for unknown template field")
+	}
+}
+
+func TestRenderGo(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Score", "Ratio", "Active"})
+	table.AddRow([]any{"Alice \"A\"", 30, 2.0, true})
+	table.AddRow([]any{"Bob", int64(7), 0.25, nil})
+
+	expected := `[][]any{
+	// Name, Score, Ratio, Active
+	{"Alice \"A\"", 30, 2.0, true},
+	// Name, Score, Ratio, Active
+	{"Bob", int64(7), 0.25, nil},
+}`
+	if actual := table.RenderGo(); actual != expected {
+		t.Errorf("RenderGo failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+	if src, err := format.Source([]byte("package p\n\nvar rows = " + expected + "\n")); err != nil || !strings.Contains(string(src), expected) {
+		t.Errorf("expected gofmt-compatible output, got %v", err)
+	}
+}
+
+func TestRenderRTF(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Qty"})
+	table.AddRow([]any{"{café}", 3})
+	table.SetAlign("Qty", AlignRight)
+
+	expected := `{\rtf1\ansi\deff0
+{\fonttbl{\f0 Arial;}}
+\trowd\trgaph108\cellx1152\cellx1872
+\pard\intbl\ql\b Name\b0\cell\pard\intbl\qr\b Qty\b0\cell\row
+\trowd\trgaph108\cellx1152\cellx1872
+\pard\intbl\ql \{caf\u233?\}\cell\pard\intbl\qr 3\cell\row
+}`
+	if actual := table.RenderRTF(); actual != expected {
+		t.Errorf("RenderRTF failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestRenderUnicodeRounded(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{1, "x"})
+
+	expected := `╭───┬───╮
+│ A │ B │
+├───┼───┤
+│ 1 │ x │
+╰───┴───╯`
+	if actual := table.RenderUnicodeRounded(); actual != expected {
+		t.Errorf("RenderUnicodeRounded failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestRenderUnicodeDouble(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{1, "x"})
+
+	expected := `╔═══╦═══╗
+║ A ║ B ║
+╠═══╬═══╣
+║ 1 ║ x ║
+╚═══╩═══╝`
+	if actual := table.RenderUnicodeDouble(); actual != expected {
+		t.Errorf("RenderUnicodeDouble failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestRenderUnicodeHeavy(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{1, "x"})
+	table.AddRow([]any{2, "y"})
+
+	expected := `┏━━━┳━━━┓
+┃ A ┃ B ┃
+┣━━━╋━━━┫
+┃ 1 ┃ x ┃
+┃ 2 ┃ y ┃
+┗━━━┻━━━┛`
+	if actual := table.RenderUnicodeHeavy(); actual != expected {
+		t.Errorf("RenderUnicodeHeavy failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	table.SetDataSeparator(1)
+	expected = `┏━━━┳━━━┓
+┃ A ┃ B ┃
+┡━━━╇━━━┩
+│ 1 │ x │
+├───┼───┤
+│ 2 │ y │
+└───┴───┘`
+	if actual := table.RenderUnicodeHeavyHeader(); actual != expected {
+		t.Errorf("RenderUnicodeHeavyHeader failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestSetSortByMultiple(t *testing.T) {
+	table := NewTableWithFields([]string{"Dept", "Age", "Name"})
+	table.AddRow([]any{"ops", 30, "Carl"})
+	table.AddRow([]any{"dev", 25, "Dana"})
+	table.AddRow([]any{"ops", 41, "Abe"})
+	table.AddRow([]any{"dev", 25, "Bea"})
+	table.AddRow([]any{"dev", 9, "Eve"})
+
+	table.SetSortByMultiple([]SortKey{
+		{Field: "Dept"},
+		{Field: "Age", Reverse: true, Less: func(a, b any) bool { return a.(int) < b.(int) }},
+	})
+	expected := `+------+-----+------+
+| Dept | Age | Name |
++------+-----+------+
+| dev  | 25  | Dana |
+| dev  | 25  | Bea  |
+| dev  | 9   | Eve  |
+| ops  | 41  | Abe  |
+| ops  | 30  | Carl |
++------+-----+------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("SetSortByMultiple failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	table.SetSortBy("Name", false)
+	if rows := table.displayRows(); rows[0][2] != "Abe" {
+		t.Errorf("expected SetSortBy to replace the multi-key sort, got %v", rows[0])
+	}
+}
+
+func TestSetColumnPadding(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Qty"})
+	table.AddRow([]any{"tea", 3})
+	table.SetStyle(TableStyle{PaddingWidth: 2})
+	table.SetColumnPadding("Qty", 4, 0)
+
+	expected := `+--------+-------+
+|  Name  |    Qty|
++--------+-------+
+|  tea   |    3  |
++--------+-------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("SetColumnPadding failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	table.SetStyle(TableStyle{LeftPaddingWidth: 3})
+	expected = `+--------+-------+
+|   Name |    Qty|
++--------+-------+
+|   tea  |    3  |
++--------+-------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("LeftPaddingWidth failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	expected = `+------+-----+
+| Name | Qty |
++------+-----+
+| tea  | 3   |
++------+-----+`
+	if actual := table.RenderMySQL(); actual != expected {
+		t.Errorf("expected RenderMySQL to ignore padding.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestRenderHTMLBootstrap(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Age"})
+	table.AddRow([]any{"<Alice>", 30})
+
+	expected := `<table class="table table-striped table-sm">
+<thead class="table-dark">
+<tr><th scope="col">Name</th><th scope="col">Age</th></tr>
+</thead>
+<tbody>
+<tr><td>&lt;Alice&gt;</td><td>30</td></tr>
+</tbody>
+</table>`
+	actual := table.RenderHTMLBootstrap(BootstrapOpts{Striped: true, Dark: true, Small: true})
+	if actual != expected {
+		t.Errorf("RenderHTMLBootstrap failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+	if actual := table.RenderHTMLBootstrap(BootstrapOpts{}); !strings.HasPrefix(actual, "<table class=\"table\">\n<thead>\n") {
+		t.Errorf("unexpected default Bootstrap markup:\n%s", actual)
+	}
+}
+
+func TestSetConditionalRowStyle(t *testing.T) {
+	table := NewTableWithFields([]string{"Item", "Delta"})
+	table.AddRow([]any{"a", 5})
+	table.AddRow([]any{"b", -3})
+	table.SetConditionalRowStyle(func(_ int, row []any) RowStyle {
+		if row[1].(int) < 0 {
+			return RowStyle{FG: ColorRed, Bold: true, HTMLClass: "negative"}
+		}
+		return RowStyle{}
+	})
+
+	expected := "┌──────┬───────┐\n" +
+		"│ Item │ Delta │\n" +
+		"├──────┼───────┤\n" +
+		"│ a    │ 5     │\n" +
+		"│\x1b[1;31m b    \x1b[0m│\x1b[1;31m -3    \x1b[0m│\n" +
+		"└──────┴───────┘"
+	if actual := table.RenderANSI(); actual != expected {
+		t.Errorf("RenderANSI failed.\nExpected:\n%q\nActual:\n%q", expected, actual)
+	}
+	if !strings.Contains(table.RenderHTML(), "<tr class=\"negative\"><td>b</td>") {
+		t.Errorf("expected row class in HTML, got\n%s", table.RenderHTML())
+	}
+	if strings.Contains(table.RenderUnicode(), "\x1b") {
+		t.Error("expected no escape codes in RenderUnicode")
+	}
+}
+
+func TestPartition(t *testing.T) {
+	table := NewTableWithFields([]string{"N"})
+	for i := 1; i <= 5; i++ {
+		table.AddRow([]any{i})
+	}
+	table.SetAlign("N", AlignRight)
+	even, odd := table.Partition(func(row []any) bool { return row[0].(int)%2 == 0 })
+	if even.StoredRowCount() != 2 || odd.StoredRowCount() != 3 {
+		t.Fatalf("expected 2 and 3 rows, got %d and %d", even.StoredRowCount(), odd.StoredRowCount())
+	}
+	if odd.Rows()[2][0] != 5 || even.alignments["N"] != AlignRight {
+		t.Errorf("unexpected partition: %v %v", odd.Rows(), even.alignments)
+	}
+	if table.StoredRowCount() != 5 {
+		t.Error("expected the original table to be unchanged")
+	}
+}
+
+func TestReduce(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Qty"})
+	table.AddRow([]any{"b", 2})
+	table.AddRow([]any{"a", 5})
+	table.AddRow([]any{"c", 0})
+	table.SetRowFilter(func(row []any) bool { return row[1].(int) > 0 })
+	table.SetSortBy("Name", false)
+
+	total := table.Reduce(0, func(acc any, row []any) any { return acc.(int) + row[1].(int) })
+	if total != 7 {
+		t.Errorf("expected total 7, got %v", total)
+	}
+	names := table.Reduce("", func(acc any, row []any) any { return acc.(string) + row[0].(string) })
+	if names != "ab" {
+		t.Errorf("expected filtered rows in sort order, got %q", names)
+	}
+}
+
+func TestChain(t *testing.T) {
+	a := NewTableWithFields([]string{"X", "Y"})
+	a.AddRow([]any{1, 2})
+	a.SetAlign("Y", AlignRight)
+	b := NewTableWithFields([]string{"X", "Y"})
+	b.AddRow([]any{3, 4})
+	c := NewTableWithFields([]string{"X", "Y"})
+	c.AddRow([]any{5, 6})
+
+	chained, err := Chain(a, b, c)
+	if err != nil {
+		t.Fatalf("Chain failed: %v", err)
+	}
+	if rows := chained.Rows(); len(rows) != 3 || rows[2][1] != 6 || chained.alignments["Y"] != AlignRight {
+		t.Errorf("unexpected chained table: %v", rows)
+	}
+
+	bad1 := NewTableWithFields([]string{"Y", "X"})
+	bad2 := NewTableWithFields([]string{"X"})
+	_, err = Chain(a, bad1, b, bad2)
+	if err == nil {
+		t.Fatal("expected error for mismatched fields")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "table 1") || !strings.Contains(msg, "table 3") {
+		t.Errorf("expected all mismatches in error, got %q", msg)
+	}
+	if _, err := Chain(); err == nil {
+		t.Error("expected error for no tables")
+	}
+}
+
+func TestSetColumnEncoding(t *testing.T) {
+	table := NewTableWithFields([]string{"B64", "Hex", "Oct", "Bin"})
+	table.AddRow([]any{[]byte("hi"), []byte("hi"), "hi", []byte{5}})
+	table.AddRow([]any{nil, 42, nil, nil})
+	table.SetColumnEncoding("B64", EncBase64)
+	table.SetColumnEncoding("Hex", EncHex)
+	table.SetColumnEncoding("Oct", EncOctal)
+	table.SetColumnEncoding("Bin", EncBinary)
+
+	expected := `+------+------+---------+----------+
+| B64  | Hex  | Oct     | Bin      |
++------+------+---------+----------+
+| aGk= | 6869 | 150 151 | 00000101 |
+|      | 42   |         |          |
++------+------+---------+----------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("SetColumnEncoding failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	table.SetColumnEncoding("Oct", EncNone)
+	if !strings.Contains(table.RenderASCII(), "| hi ") {
+		t.Errorf("expected EncNone to remove the encoding, got\n%s", table.RenderASCII())
+	}
+}
+
+func TestSetEmptyTableMessage(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B"})
+	table.AddRow([]any{1, 2})
+	table.SetRowFilter(func([]any) bool { return false })
+	table.SetEmptyTableMessage("No results found")
+
+	expected := `+---+--------------+
+| A | B            |
++---+--------------+
+| No results found |
++---+--------------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("empty rows failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	expected = `┌──────────────────┐
+│ No results found │
+└──────────────────┘`
+	if actual := NewTable().RenderUnicode(); actual != "(no fields)" {
+		t.Errorf("expected default output without a message, got %q", actual)
+	}
+	empty := NewTable()
+	empty.SetEmptyTableMessage("No results found")
+	if actual := empty.RenderUnicode(); actual != expected {
+		t.Errorf("no fields failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestCellAt(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Age"})
+	table.AddRow([]any{"Bob", 25})
+	table.AddRow([]any{"Alice", 30})
+	table.SetSortBy("Name", false)
+
+	if v, err := table.CellAt(0, "Name"); err != nil || v != "Bob" {
+		t.Errorf("expected stored row value Bob, got %v, %v", v, err)
+	}
+	if err := table.SetCellAt(1, "Age", 31); err != nil {
+		t.Fatalf("SetCellAt failed: %v", err)
+	}
+	if v, _ := table.CellAt(1, "Age"); v != 31 {
+		t.Errorf("expected 31, got %v", v)
+	}
+	if _, err := table.CellAt(2, "Age"); err == nil {
+		t.Error("expected error for out of range row")
+	}
+	if err := table.SetCellAt(0, "Email", "x"); err == nil {
+		t.Error("expected error for unknown column")
+	}
+}
+
+func TestSetAutoNumberRows(t *testing.T) {
+	table := NewTableWithFields([]string{"Name"})
+	table.AddRow([]any{"a"})
+	if err := table.SetAutoNumberRows("No", 1); err != nil {
+		t.Fatalf("SetAutoNumberRows failed: %v", err)
+	}
+	table.AddRow([]any{"b"})
+	table.AddRow([]any{"c"})
+	table.InsertRow(0, []any{"z"})
+	table.DelRow(2)
+	table.AddRowMap(map[string]any{"Name": "d"})
+
+	expected := `+----+------+
+| No | Name |
++----+------+
+| 1  | z    |
+| 2  | a    |
+| 3  | c    |
+| 4  | d    |
++----+------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("SetAutoNumberRows failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	table.SetAutoNumberRows("#", 0)
+	if v, _ := table.CellAt(3, "#"); v != 3 {
+		t.Errorf("expected 0-based numbering, got %v", v)
+	}
+	if err := table.ClearAutoNumberRows(); err != nil {
+		t.Fatalf("ClearAutoNumberRows failed: %v", err)
+	}
+	if err := table.AddRow([]any{"e"}); err != nil || table.ColCount() != 1 {
+		t.Errorf("expected numbering column removed, got %v and %d columns", err, table.ColCount())
+	}
+	if err := table.SetAutoNumberRows(table.FieldNames()[0], 1); err == nil {
+		t.Error("expected error for a label naming an existing column")
+	}
+
+	if err := table.SetAutoNumberRows("#", 1); err != nil {
+		t.Fatal(err)
+	}
+	full := []any{nil, "f"}
+	table.AddRow(full)
+	if full[0] != nil {
+		t.Errorf("AddRow numbered the caller's slice: %v", full)
+	}
+}
+
+func TestRenderLatexLongtable(t *testing.T) {
+	table := NewTableWithFields([]string{"Item", "Cost"})
+	table.AddRow([]any{"R&D", 5})
+	table.SetAlign("Cost", AlignRight)
+	table.SetCaption("Budget", false)
+	table.SetLaTeXLabel("tab:budget")
+
+	expected := `\begin{longtable}{|l|r|}
+\caption{Budget}\label{tab:budget} \\
+\hline
+Item & Cost \\
+\hline
+\endfirsthead
+\hline
+Item & Cost \\
+\hline
+\endhead
+\hline
+\multicolumn{2}{r}{Continued on next page} \\
+\endfoot
+\hline
+\endlastfoot
+R\&D & 5 \\
+\end{longtable}`
+	if actual := table.RenderLatexLongtable(); actual != expected {
+		t.Errorf("RenderLatexLongtable failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestExportCSVWithOptions(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Note"})
+	table.AddRow([]any{"Zoë", "it's; fine"})
+
+	var buf bytes.Buffer
+	if err := table.ExportCSVWithBOM(&buf); err != nil {
+		t.Fatalf("ExportCSVWithBOM failed: %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte{0xEF, 0xBB, 0xBF}) || buf.String()[3:] != table.RenderCSV() {
+		t.Errorf("expected BOM followed by the CSV output, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := table.ExportCSVWithOptions(&buf, CSVExportOptions{Delimiter: ';', Quote: '\''}); err != nil {
+		t.Fatalf("ExportCSVWithOptions failed: %v", err)
+	}
+	expected := "Name;Note\nZoë;'it''s; fine'\n"
+	if buf.String() != expected {
+		t.Errorf("custom CSV mismatch.\nExpected:\n%q\nActual:\n%q", expected, buf.String())
+	}
+
+	for _, opts := range []CSVExportOptions{{Delimiter: '"'}, {Delimiter: '\n'}, {Delimiter: ';', Quote: ';'}, {Quote: '\r'}} {
+		buf.Reset()
+		if err := table.ExportCSVWithOptions(&buf, opts); err == nil || buf.Len() > 0 {
+			t.Errorf("expected an error and no output for %+v, got %v and %q", opts, err, buf.String())
+		}
+	}
+	table.SetCSVDelimiter('"')
+	if actual, expected := table.RenderCSV(), `CSV delimiter and quote are both '"'`; actual != expected {
+		t.Errorf("RenderCSV failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestFromFileToFile(t *testing.T) {
+	dir := t.TempDir()
+	table := NewTableWithFields([]string{"Name", "City"})
+	table.AddRow([]any{"Alice", "Paris"})
+	table.AddRow([]any{"Bob", "Rome, IT"})
+
+	for _, name := range []string{"people.csv", "people.tsv", "people.json", "people.dat"} {
+		path := dir + "/" + name
+		format := ""
+		if name == "people.dat" {
+			format = "csv"
+		}
+		if err := ToFile(table, path, format); err != nil {
+			t.Fatalf("ToFile(%s) failed: %v", name, err)
+		}
+		loaded, err := FromFile(path)
+		if err != nil {
+			t.Fatalf("FromFile(%s) failed: %v", name, err)
+		}
+		if actual, expected := loaded.RenderASCII(), table.RenderASCII(); actual != expected {
+			t.Errorf("%s round trip mismatch.\nExpected:\n%s\nActual:\n%s", name, expected, actual)
+		}
+	}
+	if _, err := FromFile(dir + "/missing.csv"); err == nil {
+		t.Error("expected error for missing file")
+	}
+	for _, c := range []struct{ name, format string }{{"out.xlsx", ""}, {"out.txt", "xlsx"}} {
+		if err := ToFile(table, dir+"/"+c.name, c.format); err == nil {
+			t.Errorf("expected error for ToFile(%s, %q)", c.name, c.format)
+		}
+		if _, err := os.Stat(dir + "/" + c.name); !os.IsNotExist(err) {
+			t.Errorf("expected %s not to be created", c.name)
+		}
+	}
+
+	xmlPath := dir + "/people.xml"
+	xmlData := `<?xml version="1.0"?>
+<people>
+  <person><Name>Alice</Name><City>Paris</City></person>
+  <person><Name>Bob</Name><City>Rome, IT</City></person>
+</people>`
+	if err := os.WriteFile(xmlPath, []byte(xmlData), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := FromFile(xmlPath)
+	if err != nil {
+		t.Fatalf("FromFile(people.xml) failed: %v", err)
+	}
+	if actual, expected := loaded.RenderASCII(), table.RenderASCII(); actual != expected {
+		t.Errorf("XML mismatch.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestFromJSONAndTSV(t *testing.T) {
+	table, err := FromJSON(strings.NewReader(`[{"b": 1, "a": "x"}, {"a": "y"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(table.FieldNames(), []string{"b", "a"}) || table.rows[1][0] != nil {
+		t.Errorf("FromJSON returned %v %v", table.FieldNames(), table.rows)
+	}
+	table, err = FromTSV(strings.NewReader("a\tb\n1,5\t2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.rows[0][0] != "1,5" || table.rows[0][1] != "2" {
+		t.Errorf("FromTSV returned %v", table.rows)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	table := NewTableWithFields([]string{"ID", "Description"})
+	table.AddRow([]any{1, "the quick brown fox jumps"})
+	table.AddRow([]any{2, "short"})
+	table.WrapText("Description", 11)
+
+	expected := `+----+-------------+
+| ID | Description |
++----+-------------+
+| 1  | the quick   |
+|    | brown fox   |
+|    | jumps       |
+| 2  | short       |
++----+-------------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("WrapText failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	table.WrapText("Description", 6)
+	expected = `┌────┬────────┐
+│    │ Descri │
+│ ID │ ption  │
+├────┼────────┤
+│ 1  │ the    │
+│    │ quick  │
+│    │ brown  │
+│    │ fox    │
+│    │ jumps  │
+│ 2  │ short  │
+└────┴────────┘`
+	if actual := table.RenderUnicode(); actual != expected {
+		t.Errorf("WrapText unicode failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestSetAlignmentFromTypes(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Qty", "Price", "Code"})
+	table.AddRow([]any{"tea", nil, 2.5, 7})
+	table.AddRow([]any{"cake", int64(12), 10.0, 8})
+	table.SetAlign("Code", AlignCenter)
+	table.SetAlignmentFromTypes()
+
+	expected := `+------+-----+-------+------+
+| Name | Qty | Price | Code |
++------+-----+-------+------+
+| tea  |     |   2.5 |  7   |
+| cake |  12 |    10 |  8   |
++------+-----+-------+------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("SetAlignmentFromTypes failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestSetHeaderAlign(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Amount"})
+	table.AddRow([]any{"rent", 1200})
+	table.AddRow([]any{"food", 85})
+	table.SetAlign("Amount", AlignRight)
+	table.SetHeaderAlign("Amount", AlignCenter)
+	table.SetAlign("Name", AlignRight)
+
+	expected := `+------+--------+
+| Name | Amount |
++------+--------+
+| rent |   1200 |
+| food |     85 |
++------+--------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("SetHeaderAlign failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	table.RenameColumn("Amount", "Sum")
+	expected = `┌──────┬──────┐
+│ Name │ Sum  │
+├──────┼──────┤
+│ rent │ 1200 │
+│ food │   85 │
+└──────┴──────┘`
+	if actual := table.RenderUnicode(); actual != expected {
+		t.Errorf("SetHeaderAlign after rename failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestSetMultiLineHeader(t *testing.T) {
+	table := NewTableWithFields([]string{"City", "Annual Rainfall (mm)"})
+	table.AddRow([]any{"Adelaide", 600.5})
+	table.AddRow([]any{"Darwin", 1714.7})
+	table.SetMultiLineHeader("Annual Rainfall (mm)", []string{"Annual", "Rainfall", "(mm)"})
+	table.SetAlign("Annual Rainfall (mm)", AlignRight)
+
+	expected := `+----------+----------+
+|          |   Annual |
+|          | Rainfall |
+| City     |     (mm) |
++----------+----------+
+| Adelaide |    600.5 |
+| Darwin   |   1714.7 |
++----------+----------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("SetMultiLineHeader failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+	if !strings.Contains(table.RenderUnicode(), "│ City     │     (mm) │") {
+		t.Errorf("expected multi-line header in RenderUnicode, got\n%s", table.RenderUnicode())
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	table := NewTableWithFields([]string{"A", "B", "C"})
+	if idx := table.ColumnIndex("C"); idx != 2 {
+		t.Errorf("expected index 2, got %d", idx)
+	}
+	if idx := table.ColumnIndex("D"); idx != -1 {
+		t.Errorf("expected -1 for missing column, got %d", idx)
+	}
+	if !table.HasColumn("A") || table.HasColumn("a") {
+		t.Error("unexpected HasColumn result")
+	}
+}
+
+func TestColWidths(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Description"})
+	table.AddRow([]any{"a-long-name", "short"})
+	table.AddRow([]any{"b", "hidden by the filter"})
+	table.SetRowFilter(func(row []any) bool { return row[0] != "b" })
+	table.SetColumnFormat("Description", "[%s]")
+
+	widths := table.ColWidths()
+	if len(widths) != 2 || widths[0] != 11 || widths[1] != 11 {
+		t.Errorf("expected [11 11], got %v", widths)
+	}
+	table.SetColumnMaxWidth("Name", 4)
+	if widths := table.ColWidths(); widths[0] != 4 {
+		t.Errorf("expected max width to apply, got %v", widths)
+	}
+}
+
+func TestSetHRuleEvery(t *testing.T) {
+	table := NewTableWithFields([]string{"N"})
+	for i := 1; i <= 5; i++ {
+		table.AddRow([]any{i})
+	}
+	table.SetHRuleEvery(2)
+	expected := `┌───┐
+│ N │
+├───┤
+│ 1 │
+│ 2 │
+├───┤
+│ 3 │
+│ 4 │
+├───┤
+│ 5 │
+└───┘`
+	if actual := table.RenderUnicode(); actual != expected {
+		t.Errorf("SetHRuleEvery failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+	table.SetHRuleEvery(-1)
+	if strings.Count(table.RenderASCII(), "+---+") != 3 {
+		t.Errorf("expected rules disabled, got\n%s", table.RenderASCII())
+	}
+}
+
+func TestRenderPlainText(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "N"})
+	table.AddRow([]any{"foo", 1})
+	table.AddRow([]any{"barbaz", 22})
+	table.SetAlign("N", AlignRight)
+
+	expected := "Name     N\n" +
+		"foo      1\n" +
+		"barbaz  22"
+	if actual := table.RenderPlainText(); actual != expected {
+		t.Errorf("RenderPlainText failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestSetRowHighlight(t *testing.T) {
+	table := NewTableWithFields([]string{"N"})
+	table.AddRow([]any{1})
+	table.AddRow([]any{2})
+	table.SetRowHighlight(1, StyleBold)
+	table.SetRowHighlight(0, CellStyle{FG: ColorGreen, Underline: true})
+
+	if strings.Contains(table.RenderANSI(), "\x1b") {
+		t.Error("expected no escape codes while ANSIEnabled is unset")
+	}
+	table.SetStyle(TableStyle{ANSIEnabled: true})
+	actual := table.RenderANSI()
+	if !strings.Contains(actual, "│\x1b[4;32m 1 \x1b[0m│") || !strings.Contains(actual, "│\x1b[1m 2 \x1b[0m│") {
+		t.Errorf("expected highlighted rows, got %q", actual)
+	}
+	table.ClearRowHighlight(0)
+	if strings.Contains(table.RenderANSI(), "\x1b[4;32m") {
+		t.Error("expected row 0 highlight removed")
+	}
+	table.ClearAllHighlights()
+	if strings.Contains(table.RenderANSI(), "\x1b") {
+		t.Error("expected all highlights removed")
+	}
+}
+
+func TestSetHighlightCondition(t *testing.T) {
+	table := NewTableWithFields([]string{"Item", "Delta"})
+	table.AddRow([]any{"a", 5})
+	table.AddRow([]any{"b", -3})
+	table.AddRow([]any{"c", 120})
+	table.SetStyle(TableStyle{ANSIEnabled: true})
+	table.SetHighlightCondition("Delta", func(v any) bool { return v.(int) < 0 }, CellStyle{FG: ColorRed})
+	table.SetHighlightCondition("Delta", func(v any) bool { return v.(int) > 100 }, CellStyle{FG: ColorGreen})
+	table.SetHighlightCondition("Delta", func(v any) bool { return v.(int) > 0 }, StyleBold)
+	table.SetRowHighlight(1, StyleUnderline)
+
+	expected := "┌──────┬───────┐\n" +
+		"│ Item │ Delta │\n" +
+		"├──────┼───────┤\n" +
+		"│ a    │\x1b[1m 5     \x1b[0m│\n" +
+		"│\x1b[4m b    \x1b[0m│\x1b[31m -3    \x1b[0m│\n" +
+		"│ c    │\x1b[32m 120   \x1b[0m│\n" +
+		"└──────┴───────┘"
+	if actual := table.RenderANSI(); actual != expected {
+		t.Errorf("SetHighlightCondition failed.\nExpected:\n%q\nActual:\n%q", expected, actual)
+	}
+}
+
+func TestRenderBorderless(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "N"})
+	table.AddRow([]any{"foo", 1})
+	table.AddRow([]any{"barbaz", 22})
+	table.SetAlign("N", AlignRight)
+
+	expected := "Name\tN\nfoo\t1\nbarbaz\t22"
+	if actual := table.RenderBorderless("\t", false); actual != expected {
+		t.Errorf("RenderBorderless failed.\nExpected:\n%q\nActual:\n%q", expected, actual)
+	}
+	expected = "Name   |  N\nfoo    |  1\nbarbaz | 22"
+	if actual := table.RenderBorderless(" | ", true); actual != expected {
+		t.Errorf("padded RenderBorderless failed.\nExpected:\n%q\nActual:\n%q", expected, actual)
+	}
+}
+
+func TestRenderDiff(t *testing.T) {
+	before := NewTableWithFields([]string{"ID", "Name"})
+	before.AddRow([]any{1, "alice"})
+	before.AddRow([]any{2, "bob"})
+	before.AddRow([]any{3, "carol"})
+	after := NewTableWithFields([]string{"ID", "Name"})
+	after.AddRow([]any{1, "alice"})
+	after.AddRow([]any{2, "bobby"})
+	after.AddRow([]any{3, "carol"})
+	after.AddRow([]any{4, "dave"})
+
+	expected := `+---+----+-------+
+|   | ID | Name  |
++---+----+-------+
+|   | 1  | alice |
+| - | 2  | bob   |
+| + | 2  | bobby |
+|   | 3  | carol |
+| + | 4  | dave  |
++---+----+-------+`
+	if actual := before.RenderDiff(after); actual != expected {
+		t.Errorf("RenderDiff failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	before.SetStyle(TableStyle{ANSIEnabled: true})
+	actual := before.RenderDiff(after)
+	if !strings.Contains(actual, "\x1b[31m - \x1b[0m") || !strings.Contains(actual, "\x1b[32m + \x1b[0m") {
+		t.Errorf("expected colored diff rows, got %q", actual)
+	}
+
+	wider := NewTableWithFields([]string{"ID", "Name", "Email"})
+	wider.AddRow([]any{1, "alice", "a@example.com"})
+	var buf bytes.Buffer
+	if err := before.RenderDiffToWriter(&buf, wider); err == nil {
+		t.Error("expected error for tables with different fields")
+	}
+	unnamed, otherUnnamed := NewTable(), NewTable()
+	unnamed.AddRow([]any{1})
+	otherUnnamed.AddRow([]any{1, 2})
+	if actual := unnamed.RenderDiff(otherUnnamed); actual != "(no fields)" {
+		t.Errorf("expected (no fields), got %q", actual)
+	}
+}
+
+func TestSetTableComment(t *testing.T) {
+	table := NewTableWithFields([]string{"A"})
+	table.AddRow([]any{1})
+	table.SetTableComment("generated by report -- 2024-01-02")
+
+	if html := table.RenderHTML(); !strings.HasPrefix(html, "<!-- generated by report - - 2024-01-02 -->\n<table") {
+		t.Errorf("expected HTML comment, got\n%s", html)
+	}
+	if latex := table.RenderLaTeX(); !strings.HasPrefix(latex, "% generated by report -- 2024-01-02\n\\begin{tabular}") {
+		t.Errorf("expected LaTeX comment, got\n%s", latex)
+	}
+	if table.GetTableComment() != "generated by report -- 2024-01-02" {
+		t.Errorf("unexpected comment %q", table.GetTableComment())
+	}
+	for name, html := range map[string]string{
+		"RenderHTMLDataTable": table.RenderHTMLDataTable(DataTableOptions{}),
+		"RenderHTMLBootstrap": table.RenderHTMLBootstrap(BootstrapOpts{}),
+		"RenderEmailHTML":     table.RenderEmailHTML(),
+		"RenderHTMLSortable":  table.RenderHTMLSortable(),
+		"RenderHTMLWithCSS":   table.RenderHTMLWithCSS(map[string]string{"td": "padding: 2px"}),
+	} {
+		if !strings.HasPrefix(html, "<!-- generated by report - - 2024-01-02 -->\n") {
+			t.Errorf("expected HTML comment from %s, got\n%s", name, html)
+		}
+	}
+	table.ClearTableComment()
+	if strings.Contains(table.RenderHTML(), "<!--") {
+		t.Error("expected comment removed")
+	}
+}
+
+func TestSortStable(t *testing.T) {
+	table := NewTableWithFields([]string{"City", "Country"})
+	table.AddRow([]any{"Paris", "US"})
+	table.AddRow([]any{"Berlin", "DE"})
+	table.AddRow([]any{"Paris", "FR"})
+	table.SetSortBy("Country", false)
+	table.SortStable("City", false)
+
+	expected := `+--------+---------+
+| City   | Country |
++--------+---------+
+| Berlin | DE      |
+| Paris  | FR      |
+| Paris  | US      |
++--------+---------+`
+	actual := table.RenderASCII()
+	if actual != expected {
+		t.Errorf("SortStable failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestTopNBottomN(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Score"})
+	table.AddRow([]any{"a", 3})
+	table.AddRow([]any{"b", 7})
+	table.AddRow([]any{"c", 5})
+	table.SetSortBy("Name", true)
+
+	top, err := table.TopN(2, "Score")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `+------+-------+
+| Name | Score |
++------+-------+
+| b    | 7     |
+| c    | 5     |
++------+-------+`
+	if actual := top.RenderASCII(); actual != expected {
+		t.Errorf("TopN failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	bottom, err := table.BottomN(10, "Score")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bottom.RowCount() != 3 || bottom.rows[0][0] != "a" {
+		t.Errorf("BottomN returned %v", bottom.rows)
+	}
+	if table.sortBy != "Name" || !table.reverseSort {
+		t.Error("TopN should not change the table's sort")
+	}
+	if _, err := table.TopN(1, "Missing"); err == nil {
+		t.Error("expected error for unknown column")
+	}
+	scores := NewTableWithFields([]string{"Score"})
+	for _, v := range []any{9, 100, 25.5, 3} {
+		scores.AddRow([]any{v})
+	}
+	top, _ = scores.TopN(2, "Score")
+	if top.rows[0][0] != 100 || top.rows[1][0] != 25.5 {
+		t.Errorf("expected numeric ranking, got %v", top.rows)
+	}
+	bottom, _ = scores.BottomN(1, "Score")
+	if bottom.rows[0][0] != 3 {
+		t.Errorf("expected numeric ranking, got %v", bottom.rows)
+	}
+}
+
+func TestSetDefaultValue(t *testing.T) {
+	table := NewTableWithFields([]string{"Name"})
+	table.AddRow([]any{"a"})
+	table.AddColumn("Status", []any{"new"})
+	table.SetDefaultValue("Status", "pending")
+	if err := table.AddRow([]any{"b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.AddRowMap(map[string]any{"Name": "c"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.AddRow([]any{}); err == nil {
+		t.Error("expected error for a missing column without a default")
+	}
+
+	table.SetAutoNumberRows("#", 1)
+	if err := table.AddRow([]any{"d"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `+---+------+---------+
+| # | Name | Status  |
++---+------+---------+
+| 1 | a    | new     |
+| 2 | b    | pending |
+| 3 | c    | pending |
+| 4 | d    | pending |
++---+------+---------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("SetDefaultValue failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestValidateRequired(t *testing.T) {
+	table := NewTableWithFields([]string{"ID", "Name", "Email"})
+	table.AddRow([]any{1, "a", nil})
+	table.AddRow([]any{2, nil, nil})
+	table.SetColumnRequired("Name")
+	if errs := table.ValidateRequired(); len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+
+	table.SetColumnRequired("Email")
+	errs := table.ValidateRequired()
+	var got []string
+	for _, err := range errs {
+		if !errors.Is(err, ErrRequired) {
+			t.Errorf("expected ErrRequired, got %v", err)
+		}
+		got = append(got, err.Error())
+	}
+	expected := []string{
+		`row 0, column "Email": value is required`,
+		`row 1, column "Name": value is required`,
+		`row 1, column "Email": value is required`,
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("ValidateRequired failed.\nExpected:\n%s\nActual:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestRenderSSV(t *testing.T) {
+	table := NewTableWithFields([]string{"Code", "Name", "Qty"})
+	table.AddRow([]any{"A1", "Widget", 5})
+	table.AddRow([]any{"B22", "Gear", 120})
+	table.SetAlign("Qty", AlignRight)
+
+	expected := "Code Name   Qty\n" +
+		"A1   Widget   5\n" +
+		"B22  Gear   120"
+	if actual := table.RenderSSV(); actual != expected {
+		t.Errorf("RenderSSV failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestFromRows(t *testing.T) {
+	table, err := FromRows([]string{"Name", "Age"}, [][]string{{"Alice", "30"}, {"Bob", "25"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `+-------+-----+
+| Name  | Age |
++-------+-----+
+| Alice | 30  |
+| Bob   | 25  |
++-------+-----+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("FromRows failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+	if _, err := FromRows([]string{"Name", "Age"}, [][]string{{"Carol"}}); err == nil {
+		t.Error("expected error for short row")
+	}
+}
+
+func TestSetCSVDelimiter(t *testing.T) {
+	table := NewTableWithFields([]string{"Item", "Price"})
+	table.AddRow([]any{"Tea", "2,50"})
+	table.SetCSVDelimiter(';')
+
+	expected := "Item;Price\nTea;2,50\n"
+	if actual := table.RenderCSV(); actual != expected {
+		t.Errorf("SetCSVDelimiter failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+	var buf bytes.Buffer
+	table.ExportCSVWithOptions(&buf, CSVExportOptions{Delimiter: '\t'})
+	if buf.String() != "Item\tPrice\nTea\t2,50\n" {
+		t.Errorf("explicit delimiter should win, got %q", buf.String())
+	}
+}
+
+func TestSetAutoWrapText(t *testing.T) {
+	table := NewTableWithFields([]string{"ID", "Title", "Notes"})
+	table.AddRow([]any{1, "a fairly long title", "short note that wraps too"})
+	table.SetAutoWrapText(36)
+
+	expected := `+----+--------------+--------------+
+| ID | Title        | Notes        |
++----+--------------+--------------+
+| 1  | a fairly     | short note   |
+|    | long title   | that wraps   |
+|    |              | too          |
++----+--------------+--------------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("SetAutoWrapText failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	// A per-column WrapText setting takes precedence
+	table.WrapText("Title", 5)
+	if widths := table.ColWidths(); widths[1] != 5 || widths[2] != 19 {
+		t.Errorf("unexpected widths %v", widths)
+	}
+
+	table.SetAutoWrapText(0)
+	table.WrapText("Title", 0)
+	if widths := table.ColWidths(); widths[2] != 25 {
+		t.Errorf("expected auto-wrap off, got widths %v", widths)
+	}
+}
+
+func TestRenderMoinMoin(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Expr"})
+	table.AddRow([]any{"or", "a || b"})
+	table.AddRow([]any{"and", "a && b"})
+
+	expected := "||'''Name'''||'''Expr'''||\n" +
+		"||or||a `||` b||\n" +
+		"||and||a && b||\n"
+	if actual := table.RenderMoinMoin(); actual != expected {
+		t.Errorf("RenderMoinMoin failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestSetGroupSeparator(t *testing.T) {
+	table := NewTableWithFields([]string{"City", "Name"})
+	table.AddRow([]any{"Berlin", "Anna"})
+	table.AddRow([]any{"Berlin", "Ben"})
+	table.AddRow([]any{"Paris", "Chloe"})
+	newCity := func(prev, curr []any) bool { return prev[0] != curr[0] }
+
+	table.SetGroupSeparator(newCity, GroupSepLabel)
+	expected := `+--------+-------+
+| City   | Name  |
++--------+-------+
+| Berlin | Anna  |
+| Berlin | Ben   |
++---- Paris -----+
+| Paris  | Chloe |
++--------+-------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("GroupSepLabel failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	table.SetGroupSeparator(newCity, GroupSepBlank)
+	expected = `+--------+-------+
+| City   | Name  |
++--------+-------+
+| Berlin | Anna  |
+| Berlin | Ben   |
+|        |       |
+| Paris  | Chloe |
++--------+-------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("GroupSepBlank failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	table.SetGroupSeparator(newCity, GroupSepLine)
+	table.SetDataSeparator(2)
+	expected = `+--------+-------+
+| City   | Name  |
++--------+-------+
+| Berlin | Anna  |
+| Berlin | Ben   |
++--------+-------+
+| Paris  | Chloe |
++--------+-------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("GroupSepLine failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestExplodeColumn(t *testing.T) {
+	table := NewTableWithFields([]string{"User", "Tags"})
+	table.AddRow([]any{"alice", []string{"admin", "dev"}})
+	table.AddRow([]any{"bob", []any{}})
+	table.AddRow([]any{"carol", "ops"})
+
+	exploded, err := table.ExplodeColumn("Tags")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `+-------+-------+
+| User  | Tags  |
++-------+-------+
+| alice | admin |
+| alice | dev   |
+| bob   |       |
+| carol | ops   |
++-------+-------+`
+	if actual := exploded.RenderASCII(); actual != expected {
+		t.Errorf("ExplodeColumn failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+	if table.StoredRowCount() != 3 {
+		t.Error("ExplodeColumn should not change the table")
+	}
+	if _, err := table.ExplodeColumn("Missing"); err == nil {
+		t.Error("expected error for unknown column")
+	}
+}
+
+func TestSetSortLocale(t *testing.T) {
+	table := NewTableWithFields([]string{"Name"})
+	for _, name := range []string{"Zeller", "Äpfel", "Apfel", "Bauer"} {
+		table.AddRow([]any{name})
+	}
+	table.SetSortBy("Name", false)
+
+	names := func() string {
+		var out []string
+		for _, row := range table.displayRows() {
+			out = append(out, row[0].(string))
+		}
+		return strings.Join(out, " ")
+	}
+	if got := names(); got != "Apfel Bauer Zeller Äpfel" {
+		t.Errorf("byte order failed, got %s", got)
+	}
+	table.SetSortLocale("de-DE")
+	if got := names(); got != "Apfel Äpfel Bauer Zeller" {
+		t.Errorf("SetSortLocale failed, got %s", got)
+	}
+}
+
+func TestZip(t *testing.T) {
+	before := NewTableWithFields([]string{"Name", "Score"})
+	before.AddRow([]any{"a", 1})
+	before.AddRow([]any{"b", 2})
+	before.AddRow([]any{"c", 3})
+	after := NewTableWithFields([]string{"Name", "Score"})
+	after.AddRow([]any{"a", 10})
+	after.AddRow([]any{"b", 20})
+
+	zipped, err := before.Zip(after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `+------+-------+------------+-------------+
+| Name | Score | right_Name | right_Score |
++------+-------+------------+-------------+
+| a    | 1     |            |             |
+|      |       | a          | 10          |
+| b    | 2     |            |             |
+|      |       | b          | 20          |
+| c    | 3     |            |             |
++------+-------+------------+-------------+`
+	if actual := zipped.RenderASCII(); actual != expected {
+		t.Errorf("Zip failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestRenderHTMLWithCSS(t *testing.T) {
+	table := NewTableWithFields([]string{"A"})
+	table.AddRow([]any{1})
+
+	html := table.RenderHTMLWithCSS(map[string]string{
+		"table":              "border-collapse: collapse",
+		"th, td":             "padding: 4px",
+		"tr:nth-child(even)": "background: #eee",
+	})
+	start := strings.Index(html, `class="`) + len(`class="`)
+	class := html[start : start+strings.IndexByte(html[start:], '"')]
+	if !strings.HasPrefix(class, "prettytable-") {
+		t.Fatalf("expected a scoping class, got\n%s", html)
+	}
+	expected := "<style>\n" +
+		"table." + class + " { border-collapse: collapse }\n" +
+		"." + class + " th, ." + class + " td { padding: 4px }\n" +
+		"." + class + " tr:nth-child(even) { background: #eee }\n" +
+		"</style>\n" +
+		`<table border="1" class="` + class + `">` + "\n" +
+		"<tr><th>A</th></tr>\n<tr><td>1</td></tr>\n</table>"
+	if html != expected {
+		t.Errorf("RenderHTMLWithCSS failed.\nExpected:\n%s\nActual:\n%s", expected, html)
+	}
+	if table.RenderHTMLWithCSS(nil) != table.RenderHTML() {
+		t.Error("expected plain HTML without styles")
+	}
+}
+
+func TestRenderHTMLSortable(t *testing.T) {
+	table := NewTableWithFields([]string{"Name", "Qty"})
+	table.AddRow([]any{"a<b", 3})
+
+	html := table.RenderHTMLSortable()
+	if !strings.HasPrefix(html, "<script>\nfunction prettytableSort(th) {") {
+		t.Errorf("expected the sort script first, got\n%s", html)
+	}
+	expected := "</script>\n<table border=\"1\">\n<thead>\n<tr>" +
+		`<th onclick="prettytableSort(this)" style="cursor: pointer">Name</th>` +
+		`<th onclick="prettytableSort(this)" style="cursor: pointer">Qty</th>` +
+		"</tr>\n</thead>\n<tbody>\n" +
+		`<tr><td data-value="a&lt;b">a&lt;b</td><td data-value="3">3</td></tr>` +
+		"\n</tbody>\n</table>"
+	if !strings.HasSuffix(html, expected) {
+		t.Errorf("RenderHTMLSortable failed.\nExpected suffix:\n%s\nActual:\n%s", expected, html)
+	}
+}
+
+func TestFromCSVWithOptions(t *testing.T) {
+	data := "# exported data\nalice, 30\nbob, 25, extra\ncarol\n"
+	table, err := FromCSVWithOptions(strings.NewReader(data), CSVOptions{
+		Delimiter:        ',',
+		TrimLeadingSpace: true,
+		Comment:          '#',
+		FieldsPerRecord:  -1,
+		NoHeader:         true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `+---------+---------+---------+
+| Field 1 | Field 2 | Field 3 |
++---------+---------+---------+
+| alice   | 30      |         |
+| bob     | 25      | extra   |
+| carol   |         |         |
++---------+---------+---------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("FromCSVWithOptions failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	if _, err := FromCSVWithOptions(strings.NewReader("a,b\n1,2,3\n"), CSVOptions{}); err == nil {
+		t.Error("expected error for a record with too many fields")
+	}
+	lazy := "Name,Quote\nbob,say \"hi\"\n"
+	if _, err := FromCSVWithOptions(strings.NewReader(lazy), CSVOptions{LazyQuotes: true}); err != nil {
+		t.Errorf("expected LazyQuotes to accept bare quotes: %v", err)
+	}
+}
+
+// nullDriver is a database/sql driver whose single result set reports
+// sql.NullString and sql.NullInt64 as the scan types of its columns
+type nullDriver struct{}
+
+func (nullDriver) Open(string) (driver.Conn, error)         { return nullConn{}, nil }
+func (nullConn) Prepare(string) (driver.Stmt, error)        { return nullStmt{}, nil }
+func (nullConn) Close() error                               { return nil }
+func (nullConn) Begin() (driver.Tx, error)                  { return nil, errors.New("not supported") }
+func (nullStmt) Close() error                               { return nil }
+func (nullStmt) NumInput() int                              { return 0 }
+func (nullStmt) Exec([]driver.Value) (driver.Result, error) { return nil, errors.New("not supported") }
+func (nullStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &nullRows{data: [][]driver.Value{{"alice", "30"}, {"bob", nil}}}, nil
+}
+
+type nullConn struct{}
+
+type nullStmt struct{}
+
+type nullRows struct{ data [][]driver.Value }
+
+func (r *nullRows) Columns() []string { return []string{"name", "age"} }
+func (r *nullRows) Close() error      { return nil }
+func (r *nullRows) Next(dest []driver.Value) error {
+	if len(r.data) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.data[0])
+	r.data = r.data[1:]
+	return nil
+}
+func (r *nullRows) ColumnTypeScanType(i int) reflect.Type {
+	return []reflect.Type{reflect.TypeFor[sql.NullString](), reflect.TypeFor[sql.NullInt64]()}[i]
+}
+
+func TestFromDBRowsWithOptions(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite db: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE people (name TEXT, email TEXT);
+		INSERT INTO people VALUES ('alice', 'a@example.com'), ('bob', NULL)`)
+	if err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	rows, err := db.Query("SELECT name, email FROM people")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	defer rows.Close()
+
+	table, err := FromDBRowsWithOptions(rows, DBOptions{UnwrapSQLNull: true, NullString: "N/A"})
+	if err != nil {
+		t.Fatalf("FromDBRowsWithOptions error: %v", err)
+	}
+	expected := `+-------+---------------+
+| name  | email         |
++-------+---------------+
+| alice | a@example.com |
+| bob   | N/A           |
++-------+---------------+`
+	if actual := table.RenderASCII(); actual != expected {
+		t.Errorf("FromDBRowsWithOptions failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+
+	sql.Register("prettytable-null", nullDriver{})
+	db2, err := sql.Open("prettytable-null", "")
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	defer db2.Close()
+	rows2, err := db2.Query("SELECT")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	defer rows2.Close()
+	table, err = FromDBRowsWithOptions(rows2, DBOptions{UnwrapSQLNull: true})
+	if err != nil {
+		t.Fatalf("FromDBRowsWithOptions error: %v", err)
+	}
+	if got := table.rows; !reflect.DeepEqual(got, [][]any{{"alice", int64(30)}, {"bob", nil}}) {
+		t.Errorf("expected nullable scan types to be unwrapped, got %#v", got)
+	}
+
+	for _, c := range []struct {
+		in, want any
+	}{
+		{sql.NullString{String: "x", Valid: true}, "x"},
+		{sql.NullInt64{}, nil},
+		{sql.Null[float64]{V: 1.5, Valid: true}, 1.5},
+		{"plain", "plain"},
+	} {
+		if got, err := unwrapSQLNull(c.in); err != nil || got != c.want {
+			t.Errorf("unwrapSQLNull(%#v) = %v, %v; want %v", c.in, got, err, c.want)
+		}
+	}
+}
+
+func TestRenderWithoutFieldNames(t *testing.T) {
+	table := NewTable()
+	table.AddRow([]any{"a", 1})
+	table.AddRow([]any{true, nil})
+
+	if actual := table.RenderCSV(); actual != "\na,1\ntrue,\n" {
+		t.Errorf("RenderCSV failed, got %q", actual)
+	}
+	expected := "<table border=\"1\">\n<tr></tr>\n<tr><td>a</td><td>1</td></tr>\n<tr><td>true</td><td></td></tr>\n</table>"
+	if actual := table.RenderHTML(); actual != expected {
+		t.Errorf("RenderHTML failed.\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+	if actual := table.RenderAsciiDoc(); actual != "(no fields)" {
+		t.Errorf("RenderAsciiDoc failed, got %q", actual)
+	}
+	if widths := table.ColWidths(); len(widths) != 0 {
+		t.Errorf("ColWidths failed, got %v", widths)
+	}
+	if actual := table.RenderRTF(); actual != "(no fields)" {
+		t.Errorf("RenderRTF failed, got %q", actual)
+	}
+	if actual := table.RenderEmailHTML(); !strings.Contains(actual, ">a</td>") {
+		t.Errorf("RenderEmailHTML failed, got %q", actual)
+	}
+	table.EqualizeColumnWidths()
+	if err := table.fitWidth(20); err != nil {
+		t.Errorf("fitWidth failed: %v", err)
+	}
+}